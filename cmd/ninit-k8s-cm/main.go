@@ -53,6 +53,11 @@ func main() {
 		log.SetLevel(logger.TraceLevelLog)
 	}
 
+	os.Exit(run(c, log))
+}
+
+// run is separated from main to allow deferred cleanup before exit.
+func run(c *config.Config, log logger.Logger) int {
 	var wg sync.WaitGroup
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -61,10 +66,13 @@ func main() {
 	if err := configmap.Run(ctx, &wg, c, log); err != nil {
 		log.Errorf("%v\n", err)
 
-		return
+		return sysinit.FailureExitCode
 	}
 
-	if err := sysinit.Run(ctx, &wg, c, log); err != nil {
-		log.Errorf("%v\n", sysinit.GetErrorMessage(err))
+	res := sysinit.Run(ctx, &wg, c, log)
+	if res.Error != nil {
+		log.Errorf("%v\n", sysinit.GetErrorMessage(res.Error))
 	}
+
+	return res.ExitCode
 }
@@ -3,6 +3,7 @@ module github.com/s3rj1k/ninit
 go 1.16
 
 require (
+	github.com/cespare/xxhash/v2 v2.1.2
 	github.com/minio/highwayhash v1.0.2
 	github.com/s3rj1k/ninit/pkg/log/logger v0.0.0-00010101000000-000000000000
 	github.com/sirupsen/logrus v1.8.1
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/validate"
+)
+
+// Config contains auxiliary command specification,
+// new process is created from specification on every command run.
+type Config struct {
+	envPrefix string // contains command specific prefix for environment variables
+
+	path string
+	args []string
+}
+
+// New creates new command config.
+func New(prefix string) *Config {
+	return &Config{
+		envPrefix: prefix,
+	}
+}
+
+// Chain returns commands found in environ in execution order,
+// first command is defined by `<prefix>PATH` environment variable,
+// following ones by `<prefix><N>_PATH` environment variables ordered by N.
+func Chain(prefix string) ([]*Config, error) {
+	chain := make([]*Config, 0)
+
+	first := New(prefix)
+	if err := first.Get(); err != nil {
+		return nil, err
+	}
+
+	if first.GetPath() != "" {
+		chain = append(chain, first)
+	}
+
+	for _, index := range Indexes(prefix) {
+		c := New(prefix + index + "_")
+		if err := c.Get(); err != nil {
+			return nil, err
+		}
+
+		// whitespace-only value also defines chained command
+		if c.GetPath() == "" {
+			return nil, fmt.Errorf("%sPATH: command path is empty", c.GetEnvPrefix())
+		}
+
+		chain = append(chain, c)
+	}
+
+	return chain, nil
+}
+
+// Indexes returns list of chained command indexes found in environ sorted in numeric order,
+// chained command is defined when `<prefix><N>_PATH` environment variable exists.
+func Indexes(prefix string) []string {
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "([0-9]+)_PATH=")
+
+	indexes := make([]string, 0)
+
+	for _, env := range os.Environ() {
+		m := re.FindStringSubmatch(env)
+		if m == nil {
+			continue
+		}
+
+		indexes = append(indexes, m[1])
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, _ := strconv.ParseUint(indexes[i], 10, 64)
+		b, _ := strconv.ParseUint(indexes[j], 10, 64)
+
+		if a != b {
+			return a < b
+		}
+
+		return indexes[i] < indexes[j]
+	})
+
+	return indexes
+}
+
+func (c *Config) GetArgs() []string    { return c.args }
+func (c *Config) GetEnvPrefix() string { return c.envPrefix }
+func (c *Config) GetPath() string      { return c.path }
+
+// String returns command line.
+func (c *Config) String() string {
+	return strings.Join(append([]string{c.path}, c.args...), " ")
+}
+
+// Get reads environment variables to update and validate configuration object.
+func (c *Config) Get() error {
+	if err := c.SetPath("PATH"); err != nil {
+		return err
+	}
+
+	return c.SetArgs("ARGS")
+}
+
+// SetPath reads command path from environ and updates its value inside config.
+func (c *Config) SetPath(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := validate.Executable(val); err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.path = val
+
+	return nil
+}
+
+// SetArgs reads command args from environ and updates its value inside config.
+func (c *Config) SetArgs(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.args = strings.Fields(val)
+
+	return nil
+}
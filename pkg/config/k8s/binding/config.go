@@ -0,0 +1,265 @@
+package binding
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/k8s/configmap"
+	"github.com/s3rj1k/ninit/pkg/utils"
+	"github.com/s3rj1k/ninit/pkg/validate"
+)
+
+// Config contains kubernetes object binding configuration,
+// binding syncs content of single ConfigMap or Secret into directory.
+type Config struct {
+	name      string
+	envPrefix string // contains binding specific prefix for environment variables
+
+	baseDirectory string
+	namespace     string
+	objectKind    configmap.Kind
+	objectName    string
+
+	fileMode os.FileMode
+	fileUID  int
+	fileGID  int
+}
+
+// New creates new binding config with default values,
+// namespace and owner of written files default to provided values.
+func New(name, prefix, namespace string, fileUID, fileGID int) *Config {
+	return &Config{
+		name:       name,
+		envPrefix:  prefix,
+		namespace:  namespace,
+		objectKind: configmap.KindConfigMap,
+		fileMode:   shared.DefaultK8sFileMode,
+		fileUID:    fileUID,
+		fileGID:    fileGID,
+	}
+}
+
+// Names returns sorted list of binding names found in environ,
+// binding is defined when `<prefix><NAME>_CONFIG_MAP_NAME` environment variable exists.
+func Names(prefix string) []string {
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "([A-Z0-9_]+)_CONFIG_MAP_NAME=")
+
+	names := make([]string, 0)
+
+	for _, env := range os.Environ() {
+		m := re.FindStringSubmatch(env)
+		if m == nil {
+			continue
+		}
+
+		names = append(names, m[1])
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *Config) GetBaseDirectory() string      { return c.baseDirectory }
+func (c *Config) GetEnvPrefix() string          { return c.envPrefix }
+func (c *Config) GetFileGID() int               { return c.fileGID }
+func (c *Config) GetFileMode() os.FileMode      { return c.fileMode }
+func (c *Config) GetFileUID() int               { return c.fileUID }
+func (c *Config) GetName() string               { return c.name }
+func (c *Config) GetNamespace() string          { return c.namespace }
+func (c *Config) GetObjectKind() configmap.Kind { return c.objectKind }
+func (c *Config) GetObjectName() string         { return c.objectName }
+
+// Get reads environment variables to update and validate configuration object.
+func (c *Config) Get() error {
+	if err := c.SetBaseDirectory("BASE_DIRECTORY_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetNamespace("NAMESPACE"); err != nil {
+		return err
+	}
+
+	// object kind defines default file mode, so that it is read first
+	if err := c.SetObjectKind("OBJECT_KIND"); err != nil {
+		return err
+	}
+
+	if err := c.SetObjectName("CONFIG_MAP_NAME"); err != nil {
+		return err
+	}
+
+	if err := c.SetFileMode("FILE_MODE"); err != nil {
+		return err
+	}
+
+	if err := c.SetFileUID("FILE_UID"); err != nil {
+		return err
+	}
+
+	return c.SetFileGID("FILE_GID")
+}
+
+// SetBaseDirectory reads base directory path from environ and updates its value inside config,
+// path is required and must be absolute, because stale files are cleaned from it.
+func (c *Config) SetBaseDirectory(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	err = validate.AbsoluteDirectory(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.baseDirectory = val
+
+	return nil
+}
+
+// SetNamespace reads namespace value from environ and updates its value inside config,
+// default namespace is validated when value is not set.
+func (c *Config) SetNamespace(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		val = c.namespace
+	}
+
+	err = validate.DNSLabel(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.namespace = val
+
+	return nil
+}
+
+// SetObjectKind reads object kind from environ and updates its value inside config,
+// default file mode is restricted for Secret.
+func (c *Config) SetObjectKind(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	kind, err := configmap.ParseKind(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.objectKind = kind
+
+	if kind == configmap.KindSecret {
+		c.fileMode = shared.DefaultK8sSecretFileMode
+	}
+
+	return nil
+}
+
+// SetObjectName reads object name value from environ and updates its value inside config.
+func (c *Config) SetObjectName(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	err = validate.DNSLabel(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.objectName = val
+
+	return nil
+}
+
+// SetFileMode reads mode of written files from environ and updates its value inside config.
+func (c *Config) SetFileMode(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.FileMode(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.fileMode, _ = utils.ParseFileMode(val)
+
+	return nil
+}
+
+// SetFileUID reads owner user ID of written files from environ and updates its value inside config.
+func (c *Config) SetFileUID(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.fileUID, _ = strconv.Atoi(val)
+
+	return nil
+}
+
+// SetFileGID reads owner group ID of written files from environ and updates its value inside config.
+func (c *Config) SetFileGID(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.fileGID, _ = strconv.Atoi(val)
+
+	return nil
+}
@@ -2,23 +2,64 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/s3rj1k/ninit/pkg/config/k8s/binding"
 	cfg "github.com/s3rj1k/ninit/pkg/config/minimal"
 	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/k8s/configmap"
 	"github.com/s3rj1k/ninit/pkg/validate"
 )
 
 const DescriptionBody = `
 	- %PREFIX%K8S_BASE_DIRECTORY_PATH
-			base directory path to apply kubernetes ConfigMaps based on received event:
+			absolute base directory path [required] to apply kubernetes ConfigMaps or Secrets based on received event:
 				- ADDED, MODIFIED: file content is written to directory %PREFIX%K8S_BASE_DIRECTORY_PATH,
-					files are named based on KEY values from ConfigMap Data and BinaryData sections.
-				- DELETED: all regular files inside %PREFIX%K8S_BASE_DIRECTORY_PATH are deleted.
+					files are named based on KEY values from ConfigMap Data and BinaryData sections or Secret Data section,
+					whole key set is replaced atomically using same layout as kubelet uses for ConfigMap and Secret volumes:
+					files are written to '..<timestamp>' directory, '..data' symlink is switched to it
+					and every '<KEY>' is a symlink to '..data/<KEY>'.
+					Only keys with changed content are written, files of unchanged keys keep their modification time,
+					nothing is written when content of all keys is unchanged.
+				- DELETED: object files and all regular files inside %PREFIX%K8S_BASE_DIRECTORY_PATH are deleted.
+	- %PREFIX%K8S_OBJECT_KIND
+			kind of kubernetes object to watch: 'ConfigMap' or 'Secret' (default: 'ConfigMap').
+	- %PREFIX%K8S_FILE_MODE
+			octal permission bits of written files (default: '0644' for ConfigMap, '0600' for Secret).
+	- %PREFIX%K8S_FILE_UID
+			numeric user ID of written files owner, owner is not changed when not set [default for bindings].
+	- %PREFIX%K8S_FILE_GID
+			numeric group ID of written files owner, group is not changed when not set [default for bindings].
+			Per key values are overridden by object annotations:
+				- 'ninit.io/mode.<KEY>': octal permission bits, for example '0600';
+				- 'ninit.io/uid.<KEY>': numeric user ID;
+				- 'ninit.io/gid.<KEY>': numeric group ID.
+			File with changed mode or owner is rewritten even when its content is unchanged.
 	- %PREFIX%K8S_NAMESPACE
-			specifies kubernetes namespace that contains object to watch.
+			specifies kubernetes namespace that contains object to watch [default for bindings].
 	- %PREFIX%K8S_CONFIG_MAP_NAME
-			specifies kubernetes object (ConfigMap) name.
+			specifies kubernetes object (ConfigMap or Secret) name.
+
+	- %PREFIX%K8S_BINDING_<NAME>_CONFIG_MAP_NAME
+			kubernetes object (ConfigMap or Secret) name, defining it enables bindings mode,
+			where every object is synced into its own directory by its own informer,
+			%PREFIX%K8S_BASE_DIRECTORY_PATH, %PREFIX%K8S_OBJECT_KIND, %PREFIX%K8S_FILE_MODE
+			and %PREFIX%K8S_CONFIG_MAP_NAME are not used in this mode.
+	- %PREFIX%K8S_BINDING_<NAME>_BASE_DIRECTORY_PATH
+			absolute directory path [required] to apply object files, directory can not be shared between bindings.
+	- %PREFIX%K8S_BINDING_<NAME>_NAMESPACE
+			kubernetes namespace that contains object [default %PREFIX%K8S_NAMESPACE].
+	- %PREFIX%K8S_BINDING_<NAME>_OBJECT_KIND
+			kind of kubernetes object: 'ConfigMap' or 'Secret' (default: 'ConfigMap').
+	- %PREFIX%K8S_BINDING_<NAME>_FILE_MODE
+			octal permission bits of written files (default: '0644' for ConfigMap, '0600' for Secret).
+	- %PREFIX%K8S_BINDING_<NAME>_FILE_UID
+			numeric user ID of written files owner [default %PREFIX%K8S_FILE_UID].
+	- %PREFIX%K8S_BINDING_<NAME>_FILE_GID
+			numeric group ID of written files owner [default %PREFIX%K8S_FILE_GID].
+			Writes of all bindings are serialized, path watch is paused while any of them is in progress.
 `
 
 // Redefine defaults from shared package for convenient importing.
@@ -29,9 +70,12 @@ const (
 
 // Config contains application configuration.
 type Config struct {
-	k8sBaseDirectory string
-	k8sObjectName    string
-	k8sNamespace     string
+	// defaults of bindings
+	k8sFileGID   int
+	k8sFileUID   int
+	k8sNamespace string
+
+	k8sBindings []configmap.Binding
 
 	cfg.Config
 }
@@ -39,6 +83,9 @@ type Config struct {
 // New creates new config with defaul values.
 func New(prefix string) *Config {
 	return &Config{
+		k8sFileGID: -1,
+		k8sFileUID: -1,
+
 		Config: *cfg.New(prefix),
 	}
 }
@@ -51,9 +98,7 @@ func (*Config) GetDescriptionBody() string {
 	return strings.TrimPrefix(cfg.DescriptionBody, "\n") + "\n" + strings.TrimPrefix(DescriptionBody, "\n")
 }
 
-func (c *Config) GetK8sBaseDirectory() string { return c.k8sBaseDirectory }
-func (c *Config) GetK8sNamespace() string     { return c.k8sNamespace }
-func (c *Config) GetK8sObjectName() string    { return c.k8sObjectName }
+func (c *Config) GetK8sBindings() []configmap.Binding { return c.k8sBindings }
 
 // Get reads environment variables to update and validate configuration object.
 func (c *Config) Get() error {
@@ -61,19 +106,70 @@ func (c *Config) Get() error {
 		return err //nolint: wrapcheck // error string formed in external package is styled correctly
 	}
 
-	if err := c.SetK8sBaseDirectory("K8S_BASE_DIRECTORY_PATH"); err != nil {
+	if err := c.SetK8sNamespace("K8S_NAMESPACE"); err != nil {
 		return err
 	}
 
-	if err := c.SetK8sNamespace("K8S_NAMESPACE"); err != nil {
+	if err := c.SetK8sFileUID("K8S_FILE_UID"); err != nil {
+		return err
+	}
+
+	if err := c.SetK8sFileGID("K8S_FILE_GID"); err != nil {
 		return err
 	}
 
-	return c.SetK8sObjectName("K8S_CONFIG_MAP_NAME")
+	return c.SetK8sBindings("K8S_BINDING_")
+}
+
+// SetK8sBindings reads object bindings configuration from environ and updates its value inside config,
+// when no bindings are defined, single binding is configured from application options.
+func (c *Config) SetK8sBindings(env string) error {
+	env = c.GetEnvPrefix() + env
+
+	c.k8sBindings = nil
+
+	for _, name := range binding.Names(env) {
+		b := binding.New(strings.ToLower(name), env+name+"_", c.k8sNamespace, c.k8sFileUID, c.k8sFileGID)
+		if err := b.Get(); err != nil {
+			return err //nolint: wrapcheck // error string formed in external package is styled correctly
+		}
+
+		// object files are cleaned from base directory, so that it can not be shared
+		if v := c.findBinding(b.GetBaseDirectory()); v != nil {
+			return fmt.Errorf("%s%sBASE_DIRECTORY_PATH: path '%s' is already used by binding '%s'",
+				env, name+"_", b.GetBaseDirectory(), v.GetName())
+		}
+
+		c.k8sBindings = append(c.k8sBindings, b)
+	}
+
+	if len(c.k8sBindings) != 0 {
+		return nil
+	}
+
+	// single binding mode
+	b := binding.New("", c.GetEnvPrefix()+"K8S_", c.k8sNamespace, c.k8sFileUID, c.k8sFileGID)
+	if err := b.Get(); err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.k8sBindings = append(c.k8sBindings, b)
+
+	return nil
+}
+
+func (c *Config) findBinding(baseDirectory string) configmap.Binding {
+	for _, b := range c.k8sBindings {
+		if filepath.Clean(b.GetBaseDirectory()) == filepath.Clean(baseDirectory) {
+			return b
+		}
+	}
+
+	return nil
 }
 
-// SetK8sBaseDirectory reads k8s base directory path from environ and updates its value inside config.
-func (c *Config) SetK8sBaseDirectory(env string) error {
+// SetK8sFileUID reads owner user ID of written files from environ and updates its value inside config.
+func (c *Config) SetK8sFileUID(env string) error {
 	env = c.GetEnvPrefix() + env
 
 	val, ok, err := shared.LookupEnvValue(env)
@@ -85,50 +181,58 @@ func (c *Config) SetK8sBaseDirectory(env string) error {
 		return nil
 	}
 
-	err = validate.Directory(val)
+	err = validate.Uint(val)
 	if err != nil {
 		return fmt.Errorf("%s: %w", env, err)
 	}
 
-	c.k8sBaseDirectory = val
+	c.k8sFileUID, _ = strconv.Atoi(val)
 
 	return nil
 }
 
-// SetK8sNamespace reads k8s namespace value from environ and updates its value inside config.
-func (c *Config) SetK8sNamespace(env string) error {
+// SetK8sFileGID reads owner group ID of written files from environ and updates its value inside config.
+func (c *Config) SetK8sFileGID(env string) error {
 	env = c.GetEnvPrefix() + env
 
-	val, _, err := shared.LookupEnvValue(env)
+	val, ok, err := shared.LookupEnvValue(env)
 	if err != nil {
 		return err //nolint: wrapcheck // error string formed in external package is styled correctly
 	}
 
-	err = validate.DNSLabel(val)
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
 	if err != nil {
 		return fmt.Errorf("%s: %w", env, err)
 	}
 
-	c.k8sNamespace = val
+	c.k8sFileGID, _ = strconv.Atoi(val)
 
 	return nil
 }
 
-// SetK8sObjectName reads k8s object name value from environ and updates its value inside config.
-func (c *Config) SetK8sObjectName(env string) error {
+// SetK8sNamespace reads k8s namespace value from environ and updates its value inside config.
+func (c *Config) SetK8sNamespace(env string) error {
 	env = c.GetEnvPrefix() + env
 
-	val, _, err := shared.LookupEnvValue(env)
+	val, ok, err := shared.LookupEnvValue(env)
 	if err != nil {
 		return err //nolint: wrapcheck // error string formed in external package is styled correctly
 	}
 
+	if !ok {
+		return nil
+	}
+
 	err = validate.DNSLabel(val)
 	if err != nil {
 		return fmt.Errorf("%s: %w", env, err)
 	}
 
-	c.k8sObjectName = val
+	c.k8sNamespace = val
 
 	return nil
 }
@@ -2,44 +2,245 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/s3rj1k/ninit/pkg/config/command"
+	"github.com/s3rj1k/ninit/pkg/config/rule"
+	"github.com/s3rj1k/ninit/pkg/config/service"
 	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/glob"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/probe"
+	"github.com/s3rj1k/ninit/pkg/restart"
 	"github.com/s3rj1k/ninit/pkg/signals"
 	"github.com/s3rj1k/ninit/pkg/validate"
+	"github.com/s3rj1k/ninit/pkg/watcher"
 	"golang.org/x/sys/unix"
 )
 
 const DescriptionBody = `
 Available envars configuration options:
 	- %PREFIX%COMMAND_PATH
-			path to executable [required].
+			path to executable [required, unless services are defined].
 	- %PREFIX%COMMAND_ARGS
 			command arguments.
 	- %PREFIX%WORK_DIRECTORY_PATH
 			path to application new current working directory.
 
+	- %PREFIX%SERVICE_<NAME>_COMMAND_PATH
+			path to service executable, defining it enables services mode,
+			where multiple services are supervised, each in its own process group,
+			%PREFIX%COMMAND_PATH, %PREFIX%COMMAND_ARGS, %PREFIX%WATCH_PATH
+			and %PREFIX%PRE_RELOAD_COMMAND_PATH are not used for supervision in this mode.
+	- %PREFIX%SERVICE_<NAME>_COMMAND_ARGS
+			service command arguments.
+	- %PREFIX%SERVICE_<NAME>_WORK_DIRECTORY_PATH
+			path to service new current working directory.
+	- %PREFIX%SERVICE_<NAME>_RELOAD_SIGNAL
+			OS signal what triggers service config reload [default %PREFIX%RELOAD_SIGNAL].
+	- %PREFIX%SERVICE_<NAME>_WATCH_PATH
+			file or directory path to watch (type: pulling) file changes recursevely,
+			reload signal is sent only to this service on change.
+	- %PREFIX%SERVICE_<NAME>_WATCH_METADATA
+			file attributes hashed for service watch path [default %PREFIX%WATCH_METADATA].
+	- %PREFIX%SERVICE_<NAME>_PRE_RELOAD_COMMAND_PATH
+			path to executable that is going to be run before sending reload signal
+			on service watch path change.
+	- %PREFIX%SERVICE_<NAME>_PRE_RELOAD_COMMAND_ARGS
+			service pre-reload command arguments.
+	- %PREFIX%SERVICE_<NAME>_PRE_RELOAD_COMMAND_<N>_PATH
+	- %PREFIX%SERVICE_<NAME>_PRE_RELOAD_COMMAND_<N>_ARGS
+			service chained pre-reload commands, same as %PREFIX%PRE_RELOAD_COMMAND_<N>_PATH.
+	- %PREFIX%SERVICE_<NAME>_CRITICAL
+			boolean, when critical service exits (and is not restarted)
+			all other services are terminated [default 'true'].
+
 	- %PREFIX%PRE_RELOAD_COMMAND_PATH
 			path to executable that is going to be run before
 			sending reload signal, signal will be sent
-			only on successful run of pre-reload command.
+			only on successful run of pre-reload command,
+			changed files are passed to pre-reload command as envars:
+				- NINIT_WATCH_PATH: path where change was detected;
+				- NINIT_CHANGED_FILES: newline separated list of all changed files;
+				- NINIT_ADDED_FILES, NINIT_MODIFIED_FILES, NINIT_REMOVED_FILES:
+					newline separated lists of added, modified and removed files;
+				- NINIT_CHANGES_MANIFEST: path to temporary file with
+					one '<A|M|D><TAB><PATH>' line per changed file.
 	- %PREFIX%PRE_RELOAD_COMMAND_ARGS
 			pre-reload command arguments.
+	- %PREFIX%PRE_RELOAD_COMMAND_<N>_PATH
+			path to executable of chained pre-reload command, chained commands
+			are run after %PREFIX%PRE_RELOAD_COMMAND_PATH in numeric order of N,
+			chain stops on first failed command and signal is not sent,
+			each command gets same change set envars.
+	- %PREFIX%PRE_RELOAD_COMMAND_<N>_ARGS
+			chained pre-reload command arguments.
+	- %PREFIX%PRE_RELOAD_RESTORE
+			boolean, keep last-known-good copy of watched files (taken on watch start
+			and after every successful pre-reload command run) and restore it
+			when pre-reload command fails, each file is replaced atomically
+			with its mode and owner, files added after last-known-good state are removed,
+			applies to watch rules pre-reload commands too [default 'false'].
+	- %PREFIX%PRE_RELOAD_TIMEOUT
+			time to wait for pre-reload (and rollback) command to finish,
+			after that its process group is killed with SIGKILL and reload is skipped,
+			zero disables timeout [default '30s'].
+			Pre-reload command stdout and stderr are logged line by line
+			together with its exit code and run duration.
+
+	- %PREFIX%POST_RELOAD_CHECK_COMMAND_PATH
+			path to executable that is going to be run after
+			sending reload signal, reload fails when it exits with non-zero code.
+	- %PREFIX%POST_RELOAD_CHECK_COMMAND_ARGS
+			post-reload check command arguments.
+	- %PREFIX%POST_RELOAD_CHECK_PROBE
+			HTTP or TCP probe done after sending reload signal,
+			HTTP probe fails on non 2xx/3xx status code, TCP probe fails when connection is refused,
+			example: 'http://127.0.0.1:8080/healthz' or 'tcp://127.0.0.1:80'.
+	- %PREFIX%POST_RELOAD_CHECK_TIMEOUT
+			timeout of single post-reload check attempt and of rollback command [default '5s'].
+	- %PREFIX%POST_RELOAD_CHECK_INTERVAL
+			delay before first post-reload check attempt and between attempts [default '1s'].
+	- %PREFIX%POST_RELOAD_CHECK_RETRIES
+			number of post-reload check attempts after first failed attempt [default '2'].
+	- %PREFIX%POST_RELOAD_ROLLBACK_COMMAND_PATH
+			path to executable that is going to be run when post-reload check fails,
+			changed files are passed same as to pre-reload command.
+	- %PREFIX%POST_RELOAD_ROLLBACK_COMMAND_ARGS
+			post-reload rollback command arguments.
+	- %PREFIX%POST_RELOAD_FAILURE_ACTION
+			what is done with reloaded processes when post-reload check fails [default 'none']:
+				- none: failure is only logged;
+				- restart: process is stopped with stop signal and started again, regardless of restart policy;
+				- terminate: process is stopped with stop signal and is not restarted.
+			post-reload check is done only when check command or probe is defined,
+			it is done in background, so signals are handled while check is running.
 
 	- %PREFIX%RELOAD_SIGNAL
 			OS signal what triggers application config reload [default 'SIGHUP'].
 	- %PREFIX%RELOAD_SIGNAL_TO_PGID
 			boolean, send reload signal to PGID instead of PID.
 	- %PREFIX%SIGNAL_TO_DIRECT_CHILD_ONLY
-			boolean, signals (excluding reload signal) are only forwarded
+			boolean, signals (excluding reload and stop signals) are only forwarded
 			to direct child and not to any of its descendants,
 			meaning signal is sent to PID instead of PGID.
 
+	- %PREFIX%SIGNAL_REWRITE
+			comma separated list of 'SOURCE:TARGET' signal pairs,
+			received SOURCE signal is forwarded as TARGET signal,
+			empty TARGET means that SOURCE signal is not forwarded,
+			example: 'TERM:QUIT,INT:TERM,WINCH:'.
+
+	- %PREFIX%STOP_SIGNAL
+			OS signal sent to process groups to stop services, when SIGINT/SIGTERM is received
+			or critical service exits, received signal that has %PREFIX%SIGNAL_REWRITE rule
+			is forwarded according to that rule instead,
+			when received it is handled same as SIGINT/SIGTERM [default 'SIGTERM'].
+	- %PREFIX%STOP_TIMEOUT
+			time to wait for process group to exit after termination signal,
+			after that whole process group is killed with SIGKILL,
+			'0s' disables SIGKILL escalation [default '10s'].
+
+	- %PREFIX%RESTART_POLICY
+			restart policy for application process: 'never', 'on-failure' or 'always' [default 'never'],
+			'on-failure' restarts application only when it exits with non-zero code,
+			application is not restarted after termination signal (SIGINT/SIGTERM) was received.
+	- %PREFIX%RESTART_MAX_RETRIES
+			maximum number of consecutive restarts, '0' means unlimited [default '0'].
+	- %PREFIX%RESTART_BACKOFF
+			delay before first restart, doubled on each consecutive restart [default '1s'].
+	- %PREFIX%RESTART_BACKOFF_MAX
+			maximum delay between restarts [default '1m'].
+	- %PREFIX%RESTART_RESET_WINDOW
+			restart delay and retries counter are reset when application
+			was running at least for this duration [default '1m'].
+
 	- %PREFIX%WATCH_INTERVAL
 			watch (type: pulling) time interval [default '3s'].
 	- %PREFIX%WATCH_PATH
 			file or directory path to watch (type: pulling) file changes recursevely.
+	- %PREFIX%WATCH_MODE
+			how watch path changes are detected [default 'poll']:
+				- poll: path hash is computed on every watch interval tick;
+				- inotify: path hash is computed only after inotify event,
+					falls back to polling when inotify event queue overflows;
+				- hybrid: path hash is computed after inotify event and on every watch interval tick.
+	- %PREFIX%WATCH_FULL_HASH_EVERY
+			file content is rehashed only when file (inode, size, mtime, ctime) changes,
+			all files are rehashed on every N-th path hash computation,
+			'0' disables forced full rehash [default '20'].
+	- %PREFIX%WATCH_HASH
+			hash algorithm used for watch path digest: 'highwayhash', 'sha256' or 'xxhash' [default 'highwayhash'],
+			digest is logged on watch start and on every change in '<algorithm>:<base64>' format,
+			'sha256' digest has 'h1:' prefix and is same as 'golang.org/x/mod/sumdb/dirhash' Hash1
+			of watch path (when file metadata is not hashed).
+	- %PREFIX%WATCH_HASH_WORKERS
+			number of files hashed concurrently, digest does not depend on it [default '1'].
+	- %PREFIX%WATCH_HASH_IO_BUDGET
+			maximum file read rate in bytes per second during single watch path hash computation,
+			shared by all hash workers, '0' disables limit [default '0'].
+	- %PREFIX%WATCH_INCLUDE
+			comma separated list of glob patterns, only matching files are hashed,
+			patterns are matched against path relative to watch path,
+			'**' matches any number of directories, example: '**/*.conf,**/*.pem'.
+	- %PREFIX%WATCH_EXCLUDE
+			comma separated list of glob patterns, matching files are not hashed,
+			matching directories are not descended into, example: '**/*.swp,**/*.log'.
+	- %PREFIX%WATCH_SKIP_DIRS
+			comma separated list of glob patterns, matching directories
+			are not descended into, example: '**/.git,logs'.
+	- %PREFIX%WATCH_SKIP_HIDDEN
+			boolean, skip files and directories which names start with dot.
+	- %PREFIX%WATCH_SYMLINKS
+			how symlinks inside watch path are hashed [default 'content']:
+				- content: content of symlinked file is hashed, symlinked directories are not followed;
+				- target: symlink target path is hashed;
+				- follow: content of symlinked file is hashed, symlinked directories are followed,
+					symlink loops are not followed, changes inside followed directories
+					are not reported by inotify (use 'hybrid' or 'poll' watch mode);
+				- ignore: symlinks are skipped.
+			dangling symlinks are hashed by their target path.
+	- %PREFIX%WATCH_METADATA
+			comma separated list of file attributes that are hashed
+			in addition to file content, so that attribute change triggers reload:
+			'mode' (permission bits), 'owner' (uid and gid), 'xattrs' (extended attributes)
+			or 'none' [default 'none'].
+	- %PREFIX%WATCH_DEBOUNCE
+			settle window, after change is detected path is rehashed until
+			it is stable for this duration, only then single change is reported,
+			'0s' disables debounce [default '0s'].
+	- %PREFIX%WATCH_DEBOUNCE_MAX_WAIT
+			maximum delay of change report when path changes continuously,
+			'0s' disables limit [default '30s'].
+
+	- %PREFIX%WATCH_RULE_<NAME>_PATH
+			file or directory path to watch, defining it adds named watch rule,
+			each rule has its own watcher and reload action.
+	- %PREFIX%WATCH_RULE_<NAME>_INTERVAL
+			rule watch pulling interval [default %PREFIX%WATCH_INTERVAL].
+	- %PREFIX%WATCH_RULE_<NAME>_SIGNAL
+			OS signal sent on rule path change [default %PREFIX%RELOAD_SIGNAL].
+	- %PREFIX%WATCH_RULE_<NAME>_SIGNAL_TO_PGID
+			boolean, send rule signal to PGID instead of PID [default %PREFIX%RELOAD_SIGNAL_TO_PGID].
+	- %PREFIX%WATCH_RULE_<NAME>_METADATA
+			file attributes hashed for rule path [default %PREFIX%WATCH_METADATA].
+	- %PREFIX%WATCH_RULE_<NAME>_SERVICE
+			name of service that receives rule signal, all services receive it when not set.
+	- %PREFIX%WATCH_RULE_<NAME>_PRE_RELOAD_COMMAND_PATH
+			path to executable that is going to be run before sending rule signal,
+			%PREFIX%PRE_RELOAD_COMMAND_PATH is not used for watch rules.
+	- %PREFIX%WATCH_RULE_<NAME>_PRE_RELOAD_COMMAND_ARGS
+			rule pre-reload command arguments.
+	- %PREFIX%WATCH_RULE_<NAME>_PRE_RELOAD_COMMAND_<N>_PATH
+	- %PREFIX%WATCH_RULE_<NAME>_PRE_RELOAD_COMMAND_<N>_ARGS
+			rule chained pre-reload commands, same as %PREFIX%PRE_RELOAD_COMMAND_<N>_PATH.
+
+	- %PREFIX%STRICT_PID1
+			boolean, refuse to start when not run as PID 1,
+			by default init registers itself as child subreaper instead,
+			so orphaned processes are still reaped.
 
 	- %PREFIX%VERBOSE_LOGGING
 			boolean, enables verbose logginig, enable only for debugging purposes. 
@@ -59,17 +260,58 @@ type Config struct {
 
 	pause chan bool // pause path watching
 
-	workDirectory        string
-	commandPath          string
-	preReloadCommandPath string
-	commandArgs          []string
-	preReloadCommandArgs []string
+	services []*service.Config
+
+	workDirectory string
+	commandPath   string
+	commandArgs   []string
+
+	preReloadCommands []*command.Config
+	preReloadRestore  bool
+	preReloadTimeout  time.Duration
+
+	postReloadCheckCommandPath    string
+	postReloadCheckCommandArgs    []string
+	postReloadCheckProbe          probe.Probe
+	postReloadCheckTimeout        time.Duration
+	postReloadCheckInterval       time.Duration
+	postReloadCheckRetries        int
+	postReloadRollbackCommandPath string
+	postReloadRollbackCommandArgs []string
+	postReloadFailureAction       probe.Action
 
 	reloadSignal  unix.Signal
 	watchInterval time.Duration
+	watchMode     watcher.Mode
+
+	watchFullHashEvery int
+
+	watchHasher       hash.Hasher
+	watchHashWorkers  int
+	watchHashIOBudget int64
+	watchFilter       hash.Filter
+	watchSymlinks     hash.SymlinkPolicy
+	watchMetadata     hash.Metadata
+
+	watchDebounce        time.Duration
+	watchDebounceMaxWait time.Duration
+
+	watchRules []*rule.Config
+
+	stopSignal  unix.Signal
+	stopTimeout time.Duration
+
+	signalRewrite signals.Rewrite
+
+	restartPolicy      restart.Policy
+	restartMaxRetries  int
+	restartBackoff     time.Duration
+	restartBackoffMax  time.Duration
+	restartResetWindow time.Duration
 
 	signalToDirectChildOnly bool
 	reloadSignalToPGID      bool
+	strictPID1              bool
 
 	verboseLogging bool
 }
@@ -81,6 +323,26 @@ func New(prefix string) *Config {
 		reloadSignal:  unix.SIGHUP,
 		watchInterval: shared.DefaultWatchIntervalInSeconds * shared.NanosecondsInSeconds,
 		pause:         make(chan bool, 1),
+
+		preReloadTimeout: shared.DefaultPreReloadTimeoutInSeconds * shared.NanosecondsInSeconds,
+
+		postReloadCheckTimeout:  shared.DefaultPostReloadCheckTimeoutInSeconds * shared.NanosecondsInSeconds,
+		postReloadCheckInterval: shared.DefaultPostReloadCheckIntervalInSeconds * shared.NanosecondsInSeconds,
+		postReloadCheckRetries:  shared.DefaultPostReloadCheckRetries,
+
+		watchFullHashEvery: shared.DefaultWatchFullHashEvery,
+		watchHasher:        hash.HighwayHash,
+		watchHashWorkers:   shared.DefaultWatchHashWorkers,
+
+		watchDebounceMaxWait: shared.DefaultWatchDebounceMaxWaitInSeconds * shared.NanosecondsInSeconds,
+
+		stopSignal:  unix.SIGTERM,
+		stopTimeout: shared.DefaultStopTimeoutInSeconds * shared.NanosecondsInSeconds,
+
+		restartPolicy:      restart.Never,
+		restartBackoff:     shared.DefaultRestartBackoffInSeconds * shared.NanosecondsInSeconds,
+		restartBackoffMax:  shared.DefaultRestartBackoffMaxInSeconds * shared.NanosecondsInSeconds,
+		restartResetWindow: shared.DefaultRestartResetWindowInSeconds * shared.NanosecondsInSeconds,
 	}
 }
 
@@ -92,43 +354,110 @@ func (*Config) GetDefaultEnvPrefix() string { return shared.DefaultEnvPrefix }
 func (*Config) GetDefaultLogPrefix() string { return shared.DefaultLogPrefix }
 func (*Config) GetDescriptionBody() string  { return DescriptionBody }
 
-func (c *Config) GetCommandArgs() []string          { return c.commandArgs }
-func (c *Config) GetCommandPath() string            { return c.commandPath }
-func (c *Config) GetEnvPrefix() string              { return c.envPrefix }
-func (c *Config) GetPauseChannel() chan bool        { return c.pause }
-func (c *Config) GetPreReloadCommandArgs() []string { return c.preReloadCommandArgs }
-func (c *Config) GetPreReloadCommandPath() string   { return c.preReloadCommandPath }
-func (c *Config) GetReloadSignal() unix.Signal      { return c.reloadSignal }
-func (c *Config) GetReloadSignalToPGID() bool       { return c.reloadSignalToPGID }
-func (c *Config) GetSignalToDirectChildOnly() bool  { return c.signalToDirectChildOnly }
-func (c *Config) GetVerboseLogging() bool           { return c.verboseLogging }
-func (c *Config) GetWatchInterval() time.Duration   { return c.watchInterval }
-func (c *Config) GetWatchPath() string              { return c.watchPath }
-func (c *Config) GetWorkDirectory() string          { return c.workDirectory }
+func (c *Config) GetCommandArgs() []string                   { return c.commandArgs }
+func (c *Config) GetCommandPath() string                     { return c.commandPath }
+func (c *Config) GetEnvPrefix() string                       { return c.envPrefix }
+func (c *Config) GetPauseChannel() chan bool                 { return c.pause }
+func (c *Config) GetPostReloadCheckCommandArgs() []string    { return c.postReloadCheckCommandArgs }
+func (c *Config) GetPostReloadCheckCommandPath() string      { return c.postReloadCheckCommandPath }
+func (c *Config) GetPostReloadCheckInterval() time.Duration  { return c.postReloadCheckInterval }
+func (c *Config) GetPostReloadCheckProbe() probe.Probe       { return c.postReloadCheckProbe }
+func (c *Config) GetPostReloadCheckRetries() int             { return c.postReloadCheckRetries }
+func (c *Config) GetPostReloadCheckTimeout() time.Duration   { return c.postReloadCheckTimeout }
+func (c *Config) GetPostReloadFailureAction() probe.Action   { return c.postReloadFailureAction }
+func (c *Config) GetPostReloadRollbackCommandArgs() []string { return c.postReloadRollbackCommandArgs }
+func (c *Config) GetPostReloadRollbackCommandPath() string   { return c.postReloadRollbackCommandPath }
+func (c *Config) GetPreReloadCommands() []*command.Config    { return c.preReloadCommands }
+func (c *Config) GetPreReloadRestore() bool                  { return c.preReloadRestore }
+func (c *Config) GetPreReloadTimeout() time.Duration         { return c.preReloadTimeout }
+func (c *Config) GetReloadSignal() unix.Signal               { return c.reloadSignal }
+func (c *Config) GetReloadSignalToPGID() bool                { return c.reloadSignalToPGID }
+func (c *Config) GetRestartBackoff() time.Duration           { return c.restartBackoff }
+func (c *Config) GetRestartBackoffMax() time.Duration        { return c.restartBackoffMax }
+func (c *Config) GetRestartMaxRetries() int                  { return c.restartMaxRetries }
+func (c *Config) GetRestartPolicy() restart.Policy           { return c.restartPolicy }
+func (c *Config) GetRestartResetWindow() time.Duration       { return c.restartResetWindow }
+func (c *Config) GetServices() []*service.Config             { return c.services }
+func (c *Config) GetSignalRewrite() signals.Rewrite          { return c.signalRewrite }
+func (c *Config) GetSignalToDirectChildOnly() bool           { return c.signalToDirectChildOnly }
+func (c *Config) GetStopSignal() unix.Signal                 { return c.stopSignal }
+func (c *Config) GetStopTimeout() time.Duration              { return c.stopTimeout }
+func (c *Config) GetStrictPID1() bool                        { return c.strictPID1 }
+func (c *Config) GetVerboseLogging() bool                    { return c.verboseLogging }
+func (c *Config) GetWatchDebounce() time.Duration            { return c.watchDebounce }
+func (c *Config) GetWatchDebounceMaxWait() time.Duration     { return c.watchDebounceMaxWait }
+func (c *Config) GetWatchFilter() hash.Filter                { return c.watchFilter }
+func (c *Config) GetWatchFullHashEvery() int                 { return c.watchFullHashEvery }
+func (c *Config) GetWatchHashIOBudget() int64                { return c.watchHashIOBudget }
+func (c *Config) GetWatchHashWorkers() int                   { return c.watchHashWorkers }
+func (c *Config) GetWatchHasher() hash.Hasher                { return c.watchHasher }
+func (c *Config) GetWatchInterval() time.Duration            { return c.watchInterval }
+func (c *Config) GetWatchMetadata() hash.Metadata            { return c.watchMetadata }
+func (c *Config) GetWatchMode() watcher.Mode                 { return c.watchMode }
+func (c *Config) GetWatchPath() string                       { return c.watchPath }
+func (c *Config) GetWatchRules() []*rule.Config              { return c.watchRules }
+func (c *Config) GetWatchSymlinks() hash.SymlinkPolicy       { return c.watchSymlinks }
+func (c *Config) GetWorkDirectory() string                   { return c.workDirectory }
 
 // Get reads environment variables to update and validate configuration object.
 func (c *Config) Get() error { //nolint: cyclop // although cyclomatic complexity is high, function is readable due to similar setter calls
-	if err := c.SetCommandPath("COMMAND_PATH"); err != nil {
+	if err := c.SetReloadSignal("RELOAD_SIGNAL"); err != nil {
 		return err
 	}
 
-	if err := c.SetCommandArgs("COMMAND_ARGS"); err != nil {
+	if err := c.SetWorkingDirectory("WORK_DIRECTORY_PATH"); err != nil {
 		return err
 	}
 
-	if err := c.SetPreReloadCommandPath("PRE_RELOAD_COMMAND_PATH"); err != nil {
+	if err := c.SetWatchPath("WATCH_PATH"); err != nil {
 		return err
 	}
 
-	if err := c.SetPreReloadCommandArgs("PRE_RELOAD_COMMAND_ARGS"); err != nil {
+	if err := c.SetPreReloadCommands("PRE_RELOAD_COMMAND_"); err != nil {
 		return err
 	}
 
-	if err := c.SetWorkingDirectory("WORK_DIRECTORY_PATH"); err != nil {
+	if err := c.SetPreReloadRestore("PRE_RELOAD_RESTORE"); err != nil {
 		return err
 	}
 
-	if err := c.SetWatchPath("WATCH_PATH"); err != nil {
+	if err := c.SetPreReloadTimeout("PRE_RELOAD_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckCommandPath("POST_RELOAD_CHECK_COMMAND_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckCommandArgs("POST_RELOAD_CHECK_COMMAND_ARGS"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckProbe("POST_RELOAD_CHECK_PROBE"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckTimeout("POST_RELOAD_CHECK_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckInterval("POST_RELOAD_CHECK_INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadCheckRetries("POST_RELOAD_CHECK_RETRIES"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadRollbackCommandPath("POST_RELOAD_ROLLBACK_COMMAND_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadRollbackCommandArgs("POST_RELOAD_ROLLBACK_COMMAND_ARGS"); err != nil {
+		return err
+	}
+
+	if err := c.SetPostReloadFailureAction("POST_RELOAD_FAILURE_ACTION"); err != nil {
 		return err
 	}
 
@@ -136,7 +465,60 @@ func (c *Config) Get() error { //nolint: cyclop // although cyclomatic complexit
 		return err
 	}
 
-	if err := c.SetReloadSignal("RELOAD_SIGNAL"); err != nil {
+	if err := c.SetWatchMode("WATCH_MODE"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchFullHashEvery("WATCH_FULL_HASH_EVERY"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchHasher("WATCH_HASH"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchHashWorkers("WATCH_HASH_WORKERS"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchHashIOBudget("WATCH_HASH_IO_BUDGET"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchInclude("WATCH_INCLUDE"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchExclude("WATCH_EXCLUDE"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchSkipDirs("WATCH_SKIP_DIRS"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchSkipHidden("WATCH_SKIP_HIDDEN"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchSymlinks("WATCH_SYMLINKS"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchMetadata("WATCH_METADATA"); err != nil {
+		return err
+	}
+
+	// services mode is defined after application options that are defaults of single command service
+	if err := c.SetServices("SERVICE_"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchDebounce("WATCH_DEBOUNCE"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchDebounceMaxWait("WATCH_DEBOUNCE_MAX_WAIT"); err != nil {
 		return err
 	}
 
@@ -144,13 +526,122 @@ func (c *Config) Get() error { //nolint: cyclop // although cyclomatic complexit
 		return err
 	}
 
+	if err := c.SetWatchRules("WATCH_RULE_"); err != nil {
+		return err
+	}
+
 	if err := c.SetSignalToDirectChildOnly("SIGNAL_TO_DIRECT_CHILD_ONLY"); err != nil {
 		return err
 	}
 
+	if err := c.SetSignalRewrite("SIGNAL_REWRITE"); err != nil {
+		return err
+	}
+
+	if err := c.SetStopSignal("STOP_SIGNAL"); err != nil {
+		return err
+	}
+
+	if err := c.SetStopTimeout("STOP_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := c.SetRestartPolicy("RESTART_POLICY"); err != nil {
+		return err
+	}
+
+	if err := c.SetRestartMaxRetries("RESTART_MAX_RETRIES"); err != nil {
+		return err
+	}
+
+	if err := c.SetRestartBackoff("RESTART_BACKOFF"); err != nil {
+		return err
+	}
+
+	if err := c.SetRestartBackoffMax("RESTART_BACKOFF_MAX"); err != nil {
+		return err
+	}
+
+	if err := c.SetRestartResetWindow("RESTART_RESET_WINDOW"); err != nil {
+		return err
+	}
+
+	if err := c.SetStrictPID1("STRICT_PID1"); err != nil {
+		return err
+	}
+
 	return c.SetVerboseLogging("VERBOSE_LOGGING")
 }
 
+// SetServices reads services configuration from environ and updates its value inside config,
+// when no services are defined, single critical service is configured from application command options,
+// working directory, watch path, its metadata and pre-reload commands must be set before.
+func (c *Config) SetServices(env string) error {
+	env = c.envPrefix + env
+
+	c.services = nil
+
+	for _, name := range service.Names(env) {
+		s := service.New(strings.ToLower(name), env+name+"_", c.reloadSignal, c.watchMetadata)
+		if err := s.Get(); err != nil {
+			return err //nolint: wrapcheck // error string formed in external package is styled correctly
+		}
+
+		c.services = append(c.services, s)
+	}
+
+	if len(c.services) != 0 {
+		return nil
+	}
+
+	// single command mode
+	if err := c.SetCommandPath("COMMAND_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetCommandArgs("COMMAND_ARGS"); err != nil {
+		return err
+	}
+
+	c.services = append(c.services,
+		service.NewSingle(c.envPrefix, c.commandPath, c.commandArgs, c.workDirectory, c.watchPath, c.watchMetadata, c.reloadSignal, c.preReloadCommands),
+	)
+
+	return nil
+}
+
+// SetWatchRules reads watch rules configuration from environ and updates its value inside config.
+func (c *Config) SetWatchRules(env string) error {
+	env = c.envPrefix + env
+
+	c.watchRules = nil
+
+	for _, name := range rule.Names(env) {
+		r := rule.New(strings.ToLower(name), env+name+"_", c.watchInterval, c.reloadSignal, c.reloadSignalToPGID, c.watchMetadata)
+		if err := r.Get(); err != nil {
+			return err //nolint: wrapcheck // error string formed in external package is styled correctly
+		}
+
+		if r.GetService() != "" && !c.hasService(r.GetService()) {
+			return fmt.Errorf("%s%sSERVICE: service '%s' is not defined", env, name+"_", r.GetService())
+		}
+
+		c.watchRules = append(c.watchRules, r)
+	}
+
+	return nil
+}
+
+func (c *Config) hasService(name string) bool {
+	for _, s := range c.services {
+		if s.GetName() != "" && s.GetName() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // SetCommandPath reads command path from environ and updates its value inside config.
 func (c *Config) SetCommandPath(env string) error {
 	env = c.envPrefix + env
@@ -350,8 +841,22 @@ func (c *Config) SetVerboseLogging(env string) error {
 	return nil
 }
 
-// SetPreReloadCommandPath reads pre-reload command path from environ and updates its value inside config.
-func (c *Config) SetPreReloadCommandPath(env string) error {
+// SetPreReloadCommands reads chain of pre-reload commands from environ and updates its value inside config.
+func (c *Config) SetPreReloadCommands(env string) error {
+	env = c.envPrefix + env
+
+	chain, err := command.Chain(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.preReloadCommands = chain
+
+	return nil
+}
+
+// SetPreReloadRestore reads bool value from environ and updates its value inside config.
+func (c *Config) SetPreReloadRestore(env string) error {
 	env = c.envPrefix + env
 
 	val, ok, err := shared.LookupEnvValue(env)
@@ -363,25 +868,730 @@ func (c *Config) SetPreReloadCommandPath(env string) error {
 		return nil
 	}
 
-	if err := validate.Executable(val); err != nil {
+	err = validate.Bool(val)
+	if err != nil {
 		return fmt.Errorf("%s: %w", env, err)
 	}
 
-	c.preReloadCommandPath = val
+	c.preReloadRestore = strings.EqualFold(val, "true")
 
 	return nil
 }
 
-// SetPreReloadCommandArgs reads pre-reload command args from environ and updates its value inside config.
-func (c *Config) SetPreReloadCommandArgs(env string) error {
+// SetPreReloadTimeout reads pre-reload command timeout from environ and updates its value inside config.
+func (c *Config) SetPreReloadTimeout(env string) error {
 	env = c.envPrefix + env
 
-	val, _, err := shared.LookupEnvValue(env)
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.preReloadTimeout, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetRestartPolicy reads restart policy from environ and updates its value inside config.
+func (c *Config) SetRestartPolicy(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
 	if err != nil {
 		return err //nolint: wrapcheck // error string formed in external package is styled correctly
 	}
 
-	c.preReloadCommandArgs = strings.Fields(val)
+	if !ok {
+		return nil
+	}
+
+	err = validate.RestartPolicy(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.restartPolicy, _ = restart.Parse(val)
+
+	return nil
+}
+
+// SetRestartMaxRetries reads maximum number of consecutive restarts from environ and updates its value inside config.
+func (c *Config) SetRestartMaxRetries(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.restartMaxRetries, _ = strconv.Atoi(val)
+
+	return nil
+}
+
+// SetRestartBackoff reads initial restart delay from environ and updates its value inside config.
+func (c *Config) SetRestartBackoff(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.restartBackoff, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetRestartBackoffMax reads maximum restart delay from environ and updates its value inside config.
+func (c *Config) SetRestartBackoffMax(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.restartBackoffMax, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetRestartResetWindow reads restart reset window from environ and updates its value inside config.
+func (c *Config) SetRestartResetWindow(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.restartResetWindow, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetStopSignal reads stop signal from environ and updates its value inside config.
+func (c *Config) SetStopSignal(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Signal(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.stopSignal, _ = signals.Parse(val)
+
+	return nil
+}
+
+// SetStopTimeout reads stop timeout from environ and updates its value inside config.
+func (c *Config) SetStopTimeout(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.stopTimeout, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetSignalRewrite reads signal rewrite table from environ and updates its value inside config.
+func (c *Config) SetSignalRewrite(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.SignalRewrite(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.signalRewrite, _ = signals.ParseRewrite(val)
+
+	return nil
+}
+
+// SetStrictPID1 reads bool value from environ and updates its value inside config.
+func (c *Config) SetStrictPID1(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Bool(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	if strings.EqualFold(val, "true") {
+		c.strictPID1 = true
+	}
+
+	return nil
+}
+
+// SetWatchMode reads watch mode from environ and updates its value inside config.
+func (c *Config) SetWatchMode(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.WatchMode(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchMode, _ = watcher.ParseMode(val)
+
+	return nil
+}
+
+// SetWatchFullHashEvery reads forced full rehash period from environ and updates its value inside config.
+func (c *Config) SetWatchFullHashEvery(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchFullHashEvery, _ = strconv.Atoi(val)
+
+	return nil
+}
+
+// SetWatchDebounce reads debounce settle window from environ and updates its value inside config.
+func (c *Config) SetWatchDebounce(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchDebounce, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetWatchDebounceMaxWait reads debounce maximum wait from environ and updates its value inside config.
+func (c *Config) SetWatchDebounceMaxWait(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchDebounceMaxWait, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetWatchInclude reads include glob patterns from environ and updates its value inside config.
+func (c *Config) SetWatchInclude(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Globs(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchFilter.Include, _ = glob.ParseList(val)
+
+	return nil
+}
+
+// SetWatchExclude reads exclude glob patterns from environ and updates its value inside config.
+func (c *Config) SetWatchExclude(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Globs(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchFilter.Exclude, _ = glob.ParseList(val)
+
+	return nil
+}
+
+// SetWatchSkipDirs reads skipped directories glob patterns from environ and updates its value inside config.
+func (c *Config) SetWatchSkipDirs(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Globs(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchFilter.SkipDirs, _ = glob.ParseList(val)
+
+	return nil
+}
+
+// SetWatchSkipHidden reads bool value from environ and updates its value inside config.
+func (c *Config) SetWatchSkipHidden(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Bool(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchFilter.SkipHidden = strings.EqualFold(val, "true")
+
+	return nil
+}
+
+// SetWatchSymlinks reads symlink policy from environ and updates its value inside config.
+func (c *Config) SetWatchSymlinks(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.SymlinkPolicy(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchSymlinks, _ = hash.ParseSymlinkPolicy(val)
+
+	return nil
+}
+
+// SetWatchMetadata reads hashed file metadata list from environ and updates its value inside config.
+func (c *Config) SetWatchMetadata(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Metadata(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchMetadata, _ = hash.ParseMetadata(val)
+
+	return nil
+}
+
+// SetWatchHasher reads hash algorithm name from environ and updates its value inside config.
+func (c *Config) SetWatchHasher(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Hasher(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchHasher, _ = hash.ParseHasher(val)
+
+	return nil
+}
+
+// SetWatchHashWorkers reads number of hash workers from environ and updates its value inside config.
+func (c *Config) SetWatchHashWorkers(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchHashWorkers, _ = strconv.Atoi(val)
+
+	return nil
+}
+
+// SetWatchHashIOBudget reads hash read rate limit from environ and updates its value inside config.
+func (c *Config) SetWatchHashIOBudget(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchHashIOBudget, _ = strconv.ParseInt(val, 10, 64)
+
+	return nil
+}
+
+// SetPostReloadCheckCommandPath reads post-reload check command path from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckCommandPath(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := validate.Executable(val); err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadCheckCommandPath = val
+
+	return nil
+}
+
+// SetPostReloadCheckCommandArgs reads post-reload check command args from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckCommandArgs(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.postReloadCheckCommandArgs = strings.Fields(val)
+
+	return nil
+}
+
+// SetPostReloadCheckProbe reads post-reload check probe URL from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckProbe(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Probe(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadCheckProbe, _ = probe.Parse(val)
+
+	return nil
+}
+
+// SetPostReloadCheckTimeout reads post-reload check attempt timeout from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckTimeout(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadCheckTimeout, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetPostReloadCheckInterval reads post-reload check attempts interval from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckInterval(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadCheckInterval, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetPostReloadCheckRetries reads post-reload check retries number from environ and updates its value inside config.
+func (c *Config) SetPostReloadCheckRetries(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Uint(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadCheckRetries, _ = strconv.Atoi(val)
+
+	return nil
+}
+
+// SetPostReloadRollbackCommandPath reads post-reload rollback command path from environ and updates its value inside config.
+func (c *Config) SetPostReloadRollbackCommandPath(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := validate.Executable(val); err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadRollbackCommandPath = val
+
+	return nil
+}
+
+// SetPostReloadRollbackCommandArgs reads post-reload rollback command args from environ and updates its value inside config.
+func (c *Config) SetPostReloadRollbackCommandArgs(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.postReloadRollbackCommandArgs = strings.Fields(val)
+
+	return nil
+}
+
+// SetPostReloadFailureAction reads post-reload check failure action from environ and updates its value inside config.
+func (c *Config) SetPostReloadFailureAction(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.FailureAction(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.postReloadFailureAction, _ = probe.ParseAction(val)
 
 	return nil
 }
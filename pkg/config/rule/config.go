@@ -0,0 +1,259 @@
+package rule
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/config/command"
+	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/signals"
+	"github.com/s3rj1k/ninit/pkg/validate"
+	"golang.org/x/sys/unix"
+)
+
+// Config contains watch rule configuration,
+// rule binds watch path to reload action.
+type Config struct {
+	name      string
+	envPrefix string // contains rule specific prefix for environment variables
+
+	path     string
+	interval time.Duration
+	metadata hash.Metadata
+
+	signal       unix.Signal
+	signalToPGID bool
+
+	// service name that receives reload signal, empty means all services
+	service string
+
+	preReloadCommands []*command.Config
+}
+
+// New creates new watch rule config with default values,
+// watch interval, reload signal, its target and hashed file metadata default to provided values.
+func New(name, prefix string, interval time.Duration, signal unix.Signal, signalToPGID bool, metadata hash.Metadata) *Config {
+	return &Config{
+		name:         name,
+		envPrefix:    prefix,
+		interval:     interval,
+		metadata:     metadata,
+		signal:       signal,
+		signalToPGID: signalToPGID,
+	}
+}
+
+var chainedCommandRe = regexp.MustCompile("_PRE_RELOAD_COMMAND_[0-9]+$")
+
+// Names returns sorted list of watch rule names found in environ,
+// rule is defined when `<prefix><NAME>_PATH` environment variable exists.
+func Names(prefix string) []string {
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "([A-Z0-9_]+)_PATH=")
+
+	names := make([]string, 0)
+
+	for _, env := range os.Environ() {
+		m := re.FindStringSubmatch(env)
+		if m == nil {
+			continue
+		}
+
+		// `<prefix><NAME>_PRE_RELOAD_COMMAND_PATH` and `<prefix><NAME>_PRE_RELOAD_COMMAND_<N>_PATH` also match expression
+		if strings.HasSuffix(m[1], "_PRE_RELOAD_COMMAND") || chainedCommandRe.MatchString(m[1]) {
+			continue
+		}
+
+		names = append(names, m[1])
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *Config) GetEnvPrefix() string                    { return c.envPrefix }
+func (c *Config) GetInterval() time.Duration              { return c.interval }
+func (c *Config) GetMetadata() hash.Metadata              { return c.metadata }
+func (c *Config) GetName() string                         { return c.name }
+func (c *Config) GetPath() string                         { return c.path }
+func (c *Config) GetPreReloadCommands() []*command.Config { return c.preReloadCommands }
+func (c *Config) GetService() string                      { return c.service }
+func (c *Config) GetSignal() unix.Signal                  { return c.signal }
+func (c *Config) GetSignalToPGID() bool                   { return c.signalToPGID }
+
+// Get reads environment variables to update and validate configuration object.
+func (c *Config) Get() error {
+	if err := c.SetPath("PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetInterval("INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := c.SetMetadata("METADATA"); err != nil {
+		return err
+	}
+
+	if err := c.SetSignal("SIGNAL"); err != nil {
+		return err
+	}
+
+	if err := c.SetSignalToPGID("SIGNAL_TO_PGID"); err != nil {
+		return err
+	}
+
+	if err := c.SetService("SERVICE"); err != nil {
+		return err
+	}
+
+	return c.SetPreReloadCommands("PRE_RELOAD_COMMAND_")
+}
+
+// SetPath reads watch path from environ and updates its value inside config.
+func (c *Config) SetPath(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	err = validate.FileOrDirectory(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.path = val
+
+	return nil
+}
+
+// SetInterval reads pulling interval from environ and updates its value inside config.
+func (c *Config) SetInterval(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Duration(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.interval, _ = time.ParseDuration(val)
+
+	return nil
+}
+
+// SetMetadata reads hashed file metadata list from environ and updates its value inside config.
+func (c *Config) SetMetadata(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Metadata(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.metadata, _ = hash.ParseMetadata(val)
+
+	return nil
+}
+
+// SetSignal reads reload signal from environ and updates its value inside config.
+func (c *Config) SetSignal(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Signal(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.signal, _ = signals.Parse(val)
+
+	return nil
+}
+
+// SetSignalToPGID reads bool value from environ and updates its value inside config.
+func (c *Config) SetSignalToPGID(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Bool(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.signalToPGID = strings.EqualFold(val, "true")
+
+	return nil
+}
+
+// SetService reads name of service that receives reload signal from environ and updates its value inside config.
+func (c *Config) SetService(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	c.service = strings.ToLower(val)
+
+	return nil
+}
+
+// SetPreReloadCommands reads chain of rule pre-reload commands from environ and updates its value inside config.
+func (c *Config) SetPreReloadCommands(env string) error {
+	env = c.envPrefix + env
+
+	chain, err := command.Chain(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.preReloadCommands = chain
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/s3rj1k/ninit/pkg/config/command"
+	"github.com/s3rj1k/ninit/pkg/config/shared"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/signals"
+	"github.com/s3rj1k/ninit/pkg/validate"
+	"golang.org/x/sys/unix"
+)
+
+// Config contains supervised service configuration.
+type Config struct {
+	name      string
+	envPrefix string // contains service specific prefix for environment variables
+
+	watchPath     string
+	watchMetadata hash.Metadata
+
+	workDirectory string
+	commandPath   string
+	commandArgs   []string
+
+	reloadSignal unix.Signal
+
+	critical bool
+
+	preReloadCommands []*command.Config
+}
+
+// New creates new service config with default values,
+// reload signal and hashed file metadata of watch path default to provided values.
+func New(name, prefix string, reloadSignal unix.Signal, watchMetadata hash.Metadata) *Config {
+	return &Config{
+		name:          name,
+		envPrefix:     prefix,
+		watchMetadata: watchMetadata,
+		reloadSignal:  reloadSignal,
+		critical:      true,
+	}
+}
+
+// NewSingle creates critical service config of single command mode from already parsed application options.
+func NewSingle(
+	prefix, commandPath string, commandArgs []string, workDirectory, watchPath string, watchMetadata hash.Metadata,
+	reloadSignal unix.Signal, preReloadCommands []*command.Config,
+) *Config {
+	return &Config{
+		envPrefix:         prefix,
+		watchPath:         watchPath,
+		watchMetadata:     watchMetadata,
+		workDirectory:     workDirectory,
+		commandPath:       commandPath,
+		commandArgs:       commandArgs,
+		reloadSignal:      reloadSignal,
+		critical:          true,
+		preReloadCommands: preReloadCommands,
+	}
+}
+
+// Names returns sorted list of service names found in environ,
+// service is defined when `<prefix><NAME>_COMMAND_PATH` environment variable exists.
+func Names(prefix string) []string {
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "([A-Z0-9_]+)_COMMAND_PATH=")
+
+	names := make([]string, 0)
+
+	for _, env := range os.Environ() {
+		m := re.FindStringSubmatch(env)
+		if m == nil {
+			continue
+		}
+
+		// `<prefix><NAME>_PRE_RELOAD_COMMAND_PATH` also matches expression
+		if strings.HasSuffix(m[1], "_PRE_RELOAD") {
+			continue
+		}
+
+		names = append(names, m[1])
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *Config) GetCommandArgs() []string                { return c.commandArgs }
+func (c *Config) GetCommandPath() string                  { return c.commandPath }
+func (c *Config) GetCritical() bool                       { return c.critical }
+func (c *Config) GetEnvPrefix() string                    { return c.envPrefix }
+func (c *Config) GetName() string                         { return c.name }
+func (c *Config) GetPreReloadCommands() []*command.Config { return c.preReloadCommands }
+func (c *Config) GetReloadSignal() unix.Signal            { return c.reloadSignal }
+func (c *Config) GetWatchMetadata() hash.Metadata         { return c.watchMetadata }
+func (c *Config) GetWatchPath() string                    { return c.watchPath }
+func (c *Config) GetWorkDirectory() string                { return c.workDirectory }
+
+// Get reads environment variables to update and validate configuration object.
+func (c *Config) Get() error {
+	if err := c.SetCommandPath("COMMAND_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetCommandArgs("COMMAND_ARGS"); err != nil {
+		return err
+	}
+
+	if err := c.SetWorkingDirectory("WORK_DIRECTORY_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchPath("WATCH_PATH"); err != nil {
+		return err
+	}
+
+	if err := c.SetWatchMetadata("WATCH_METADATA"); err != nil {
+		return err
+	}
+
+	if err := c.SetReloadSignal("RELOAD_SIGNAL"); err != nil {
+		return err
+	}
+
+	if err := c.SetCritical("CRITICAL"); err != nil {
+		return err
+	}
+
+	return c.SetPreReloadCommands("PRE_RELOAD_COMMAND_")
+}
+
+// SetCommandPath reads command path from environ and updates its value inside config.
+func (c *Config) SetCommandPath(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if err := validate.Executable(val); err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.commandPath = val
+
+	return nil
+}
+
+// SetCommandArgs reads command args from environ and updates its value inside config.
+func (c *Config) SetCommandArgs(env string) error {
+	env = c.envPrefix + env
+
+	val, _, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.commandArgs = strings.Fields(val)
+
+	return nil
+}
+
+// SetWorkingDirectory reads working directory path from environ and updates its value inside config.
+func (c *Config) SetWorkingDirectory(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Directory(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.workDirectory = val
+
+	return nil
+}
+
+// SetWatchPath reads watch path from environ and updates its value inside config.
+func (c *Config) SetWatchPath(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.FileOrDirectory(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchPath = val
+
+	return nil
+}
+
+// SetWatchMetadata reads hashed file metadata list of watch path from environ and updates its value inside config.
+func (c *Config) SetWatchMetadata(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Metadata(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.watchMetadata, _ = hash.ParseMetadata(val)
+
+	return nil
+}
+
+// SetReloadSignal reads reload signal from environ and updates its value inside config.
+func (c *Config) SetReloadSignal(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Signal(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.reloadSignal, _ = signals.Parse(val)
+
+	return nil
+}
+
+// SetCritical reads bool value from environ and updates its value inside config.
+func (c *Config) SetCritical(env string) error {
+	env = c.envPrefix + env
+
+	val, ok, err := shared.LookupEnvValue(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = validate.Bool(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	c.critical = strings.EqualFold(val, "true")
+
+	return nil
+}
+
+// SetPreReloadCommands reads chain of service pre-reload commands from environ and updates its value inside config.
+func (c *Config) SetPreReloadCommands(env string) error {
+	env = c.envPrefix + env
+
+	chain, err := command.Chain(env)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	c.preReloadCommands = chain
+
+	return nil
+}
@@ -5,8 +5,26 @@ const (
 	DefaultEnvPrefix = "INIT_"
 	DefaultLogPrefix = "init "
 
-	DefaultWatchIntervalInSeconds = 3
-	NanosecondsInSeconds          = 1000 * 1000 * 1000
+	DefaultWatchIntervalInSeconds        = 3
+	DefaultWatchFullHashEvery            = 20
+	DefaultWatchHashWorkers              = 1
+	DefaultWatchDebounceMaxWaitInSeconds = 30
+	NanosecondsInSeconds                 = 1000 * 1000 * 1000
+
+	DefaultRestartBackoffInSeconds     = 1
+	DefaultRestartBackoffMaxInSeconds  = 60
+	DefaultRestartResetWindowInSeconds = 60
+
+	DefaultStopTimeoutInSeconds = 10
+
+	DefaultPreReloadTimeoutInSeconds = 30
+
+	DefaultPostReloadCheckTimeoutInSeconds  = 5
+	DefaultPostReloadCheckIntervalInSeconds = 1
+	DefaultPostReloadCheckRetries           = 2
+
+	DefaultK8sFileMode       = 0o644
+	DefaultK8sSecretFileMode = 0o600
 
 	UnknownValue = "UNKNOWN"
 )
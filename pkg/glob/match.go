@@ -0,0 +1,95 @@
+package glob
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Patterns use `path.Match` syntax for single path segment,
+// additionally `**` segment matches zero or more path segments (doublestar semantics):
+//   - `*.swp` matches `a.swp` but not `dir/a.swp`;
+//   - `**/*.swp` matches `a.swp` and `dir/a.swp`;
+//   - `.git/**` matches all files inside `.git` directory.
+// Paths and patterns are slash separated.
+
+const doubleStar = "**"
+
+// Match reports whether slash separated name matches pattern.
+func Match(pattern, name string) (bool, error) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// MatchAny reports whether name matches any of patterns, patterns must be valid.
+func MatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSegments(patterns, names []string) (bool, error) {
+	if len(patterns) == 0 {
+		return len(names) == 0, nil
+	}
+
+	if patterns[0] == doubleStar {
+		for i := 0; i <= len(names); i++ {
+			ok, err := matchSegments(patterns[1:], names[i:])
+			if ok || err != nil {
+				return ok, err
+			}
+		}
+
+		return false, nil
+	}
+
+	if len(names) == 0 {
+		return false, nil
+	}
+
+	ok, err := path.Match(patterns[0], names[0])
+	if !ok || err != nil {
+		return false, err //nolint: wrapcheck // error is wrapped in exported function
+	}
+
+	return matchSegments(patterns[1:], names[1:])
+}
+
+// Validate checks that pattern is well formed.
+func Validate(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("glob pattern is invalid, empty string")
+	}
+
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("glob pattern '%s' is invalid: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseList parses comma separated list of glob patterns.
+func ParseList(val string) ([]string, error) {
+	patterns := make([]string, 0)
+
+	for _, pattern := range strings.Split(val, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		if err := Validate(pattern); err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
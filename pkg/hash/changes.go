@@ -0,0 +1,126 @@
+package hash
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Digests maps file path to hash of its content.
+type Digests map[string][]byte
+
+// Changes describes difference between two sets of file digests.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// IsEmpty reports that there are no changed files.
+func (c Changes) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Removed) == 0
+}
+
+// All returns sorted list of all changed files.
+func (c Changes) All() []string {
+	out := make([]string, 0, len(c.Added)+len(c.Modified)+len(c.Removed))
+
+	out = append(out, c.Added...)
+	out = append(out, c.Modified...)
+	out = append(out, c.Removed...)
+
+	sort.Strings(out)
+
+	return out
+}
+
+// Diff returns list of added, modified and removed files between old and new digests.
+func Diff(prev, cur Digests) Changes {
+	var c Changes
+
+	for file, sum := range cur {
+		prevSum, ok := prev[file]
+
+		switch {
+		case !ok:
+			c.Added = append(c.Added, file)
+		case !bytes.Equal(prevSum, sum):
+			c.Modified = append(c.Modified, file)
+		}
+	}
+
+	for file := range prev {
+		if _, ok := cur[file]; !ok {
+			c.Removed = append(c.Removed, file)
+		}
+	}
+
+	sort.Strings(c.Added)
+	sort.Strings(c.Modified)
+	sort.Strings(c.Removed)
+
+	return c
+}
+
+// Merge returns changes from state before c to state after next, when next changes follow c.
+func (c Changes) Merge(next Changes) Changes {
+	const (
+		added byte = iota + 1
+		modified
+		removed
+	)
+
+	state := make(map[string]byte, len(c.Added)+len(c.Modified)+len(c.Removed))
+
+	for _, file := range c.Added {
+		state[file] = added
+	}
+
+	for _, file := range c.Modified {
+		state[file] = modified
+	}
+
+	for _, file := range c.Removed {
+		state[file] = removed
+	}
+
+	for _, file := range next.Added {
+		if state[file] == removed {
+			state[file] = modified
+		} else {
+			state[file] = added
+		}
+	}
+
+	for _, file := range next.Modified {
+		if _, ok := state[file]; !ok {
+			state[file] = modified
+		}
+	}
+
+	for _, file := range next.Removed {
+		if state[file] == added {
+			delete(state, file)
+		} else {
+			state[file] = removed
+		}
+	}
+
+	var out Changes
+
+	for file, s := range state {
+		switch s {
+		case added:
+			out.Added = append(out.Added, file)
+		case modified:
+			out.Modified = append(out.Modified, file)
+		case removed:
+			out.Removed = append(out.Removed, file)
+		}
+	}
+
+	sort.Strings(out.Added)
+	sort.Strings(out.Modified)
+	sort.Strings(out.Removed)
+
+	return out
+}
@@ -0,0 +1,44 @@
+package hash
+
+import (
+	"strings"
+
+	"github.com/s3rj1k/ninit/pkg/glob"
+)
+
+// Filter selects files that are hashed, glob patterns are matched
+// against slash separated path relative to hashed path.
+type Filter struct {
+	// Include limits hashed files to ones matching any of patterns, empty list includes all files.
+	Include []string
+	// Exclude skips files matching any of patterns, matching directories are not descended into.
+	Exclude []string
+	// SkipDirs lists directory patterns that are not descended into.
+	SkipDirs []string
+	// SkipHidden skips files and directories which names start with dot.
+	SkipHidden bool
+}
+
+func (f Filter) skipDir(rel, name string) bool {
+	if f.SkipHidden && isHidden(name) {
+		return true
+	}
+
+	return glob.MatchAny(f.SkipDirs, rel) || glob.MatchAny(f.Exclude, rel)
+}
+
+func (f Filter) skipFile(rel, name string) bool {
+	if f.SkipHidden && isHidden(name) {
+		return true
+	}
+
+	if glob.MatchAny(f.Exclude, rel) {
+		return true
+	}
+
+	return len(f.Include) != 0 && !glob.MatchAny(f.Include, rel)
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -1,63 +1,100 @@
 package hash
 
 import (
-	"encoding/base64"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"strings"
-
-	"github.com/minio/highwayhash"
 )
 
-const hashKey = "31220946A728567B509734212C3295856D76134229E959910805438F52169117"
-
-// FromPath returns hash of hashes for all files inside path.
+// FromPath returns digest of all files inside path in `<hasher name>:<base64>` format,
+// it is same as FromPathWithOptions with default options.
 // This function is inspired by https://pkg.go.dev/golang.org/x/mod/sumdb/dirhash
 func FromPath(path string) (string, error) {
-	files, err := getListOfFilesFromPath(path)
+	return FromPathWithOptions(path, Options{})
+}
+
+// FromPathWithOptions returns digest of all files inside path in `<hasher name>:<base64>` format,
+// digest is computed from manifest that contains `<hex file hash>  <relative file path>` line per file.
+// When SHA256 hasher is used without metadata, digest is same as one computed by
+// `dirhash.HashDir(path, opts.Prefix, dirhash.Hash1)` from `golang.org/x/mod/sumdb/dirhash`.
+func FromPathWithOptions(path string, opts Options) (string, error) {
+	return NewTree(path, opts).Sum()
+}
+
+// fromEntry returns hash of file content or symlink target for symlink entries,
+// dangling symlink gets its own hash so that it is reported as file state instead of error,
+// file content is read within limiter budget.
+func fromEntry(h Hasher, e entry, lim *limiter) ([]byte, error) {
+	switch e.kind {
+	case entryLink:
+		return fromString(h, "symlink "+e.link)
+	case entryDangling:
+		return fromString(h, "dangling symlink "+e.link)
+	}
+
+	return fromFile(h, e.name, lim)
+}
+
+func fromString(h Hasher, val string) ([]byte, error) {
+	hf, err := h.New()
 	if err != nil {
-		return "", fmt.Errorf("hash error, path '%s': %w", path, err)
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
 	}
 
-	key, err := hex.DecodeString(hashKey)
+	_, _ = io.WriteString(hf, val)
+
+	return hf.Sum(nil), nil
+}
+
+// fromFile returns hash of file content, nil limiter does not limit read rate.
+func fromFile(h Hasher, file string, lim *limiter) ([]byte, error) {
+	r, err := os.OpenFile(file, os.O_RDONLY, 0)
 	if err != nil {
-		return "", fmt.Errorf("hash error, path '%s': %w", path, err)
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
 	}
 
-	h, err := highwayhash.New(key)
+	hf, err := h.New()
 	if err != nil {
-		return "", fmt.Errorf("hash error, path '%s': %w", path, err)
+		_ = r.Close()
+
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
 	}
 
-	for _, file := range files {
-		if strings.Contains(file, "\n") {
-			return "", fmt.Errorf("hash error, path '%s': filenames with newlines are not supported", path)
-		}
+	if lim != nil {
+		_, err = io.Copy(hf, &limitedReader{r: r, lim: lim})
+	} else {
+		_, err = io.Copy(hf, r)
+	}
 
-		r, err := os.OpenFile(file, os.O_RDONLY, 0)
-		if err != nil {
-			return "", fmt.Errorf("hash error, path '%s': %w", path, err)
-		}
+	_ = r.Close()
 
-		hf, err := highwayhash.New(key)
-		if err != nil {
-			return "", fmt.Errorf("hash error, path '%s': %w", path, err)
-		}
+	if err != nil {
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
+	}
 
-		_, err = io.Copy(hf, r)
-		_ = r.Close()
+	return hf.Sum(nil), nil
+}
 
-		if err != nil {
-			return "", fmt.Errorf("hash error, path '%s': %w", path, err)
-		}
+// fromSums returns manifest of sorted list of file hashes and its hash,
+// names contains file names used in manifest for corresponding files.
+func fromSums(h Hasher, files, names []string, sums map[string][]byte) (string, []byte, error) {
+	hf, err := h.New()
+	if err != nil {
+		return "", nil, err //nolint: wrapcheck // error is wrapped in exported function
+	}
 
-		_, err = fmt.Fprintf(h, "%x  %s\n", hf.Sum(nil), file)
-		if err != nil {
-			return "", fmt.Errorf("hash error, path '%s': %w", path, err)
+	var b strings.Builder
+
+	for i, file := range files {
+		if strings.Contains(names[i], "\n") {
+			return "", nil, fmt.Errorf("filenames with newlines are not supported")
 		}
+
+		fmt.Fprintf(&b, "%x  %s\n", sums[file], names[i])
 	}
 
-	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+	_, _ = io.WriteString(hf, b.String())
+
+	return b.String(), hf.Sum(nil), nil
 }
@@ -0,0 +1,76 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/minio/highwayhash"
+)
+
+// hashKey is HighwayHash key, it is decoded once on package init.
+var hashKey = mustDecodeHex("31220946A728567B509734212C3295856D76134229E959910805438F52169117")
+
+// Hasher creates hash functions used for file and path digests.
+type Hasher interface {
+	// Name returns hash algorithm name, it is used as path digest prefix.
+	Name() string
+	// New returns new hash function.
+	New() (hash.Hash, error)
+}
+
+// Available hashers.
+var (
+	// HighwayHash is keyed HighwayHash-256 with constant key.
+	HighwayHash Hasher = highwayHasher{}
+	// SHA256 is SHA-256, path digest is compatible with `golang.org/x/mod/sumdb/dirhash.Hash1`.
+	SHA256 Hasher = sha256Hasher{}
+	// XXHash is 64-bit xxHash.
+	XXHash Hasher = xxHasher{}
+)
+
+// ParseHasher matches hash algorithm name to hasher.
+func ParseHasher(val string) (Hasher, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "highwayhash":
+		return HighwayHash, nil
+	case "sha256", "h1":
+		return SHA256, nil
+	case "xxhash":
+		return XXHash, nil
+	}
+
+	return HighwayHash, fmt.Errorf("unknown hash algorithm value: %s", val)
+}
+
+type highwayHasher struct{}
+
+func (highwayHasher) Name() string { return "highwayhash" }
+
+func (highwayHasher) New() (hash.Hash, error) {
+	return highwayhash.New(hashKey) //nolint: wrapcheck // error is wrapped in exported function
+}
+
+func mustDecodeHex(val string) []byte {
+	b, err := hex.DecodeString(val)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Name() string { return "h1" }
+
+func (sha256Hasher) New() (hash.Hash, error) { return sha256.New(), nil }
+
+type xxHasher struct{}
+
+func (xxHasher) Name() string { return "xxhash" }
+
+func (xxHasher) New() (hash.Hash, error) { return xxhash.New(), nil }
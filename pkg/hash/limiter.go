@@ -0,0 +1,67 @@
+package hash
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// limiter limits read rate to budget bytes per second, it is shared between concurrent readers.
+type limiter struct {
+	budget int64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newLimiter returns read rate limiter, nil limiter (no limit) is returned for zero budget.
+func newLimiter(budget int64) *limiter {
+	if budget <= 0 {
+		return nil
+	}
+
+	return &limiter{budget: budget}
+}
+
+// wait blocks until n bytes can be read within budget.
+func (l *limiter) wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+
+	d := time.Duration(int64(n) * int64(time.Second) / l.budget)
+
+	l.mu.Lock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	at := l.next
+	l.next = l.next.Add(d)
+
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(at))
+}
+
+// limitedReader reads from underlying reader within limiter budget.
+type limitedReader struct {
+	r   io.Reader
+	lim *limiter
+}
+
+// limitedReadSize is maximum size of single read, so that budget is spent gradually.
+const limitedReadSize = 32 * 1024
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > limitedReadSize {
+		p = p[:limitedReadSize]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.lim.wait(n)
+
+	return n, err //nolint: wrapcheck // error is returned unchanged to io.Copy
+}
@@ -0,0 +1,204 @@
+package hash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Metadata defines which file attributes are hashed in addition to file content.
+type Metadata int
+
+// Available file metadata flags.
+const (
+	// MetadataMode hashes file permission bits.
+	MetadataMode Metadata = 1 << iota
+	// MetadataOwner hashes file owner uid and gid.
+	MetadataOwner
+	// MetadataXattrs hashes file extended attributes.
+	MetadataXattrs
+
+	// MetadataNone disables metadata hashing.
+	MetadataNone Metadata = 0
+)
+
+var metadataNames = []struct {
+	flag Metadata
+	name string
+}{
+	{MetadataMode, "mode"},
+	{MetadataOwner, "owner"},
+	{MetadataXattrs, "xattrs"},
+}
+
+// ParseMetadata parses comma separated list of metadata names ('mode', 'owner', 'xattrs') or 'none'.
+func ParseMetadata(val string) (Metadata, error) {
+	m := MetadataNone
+
+	for _, name := range strings.Split(val, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+
+		var ok bool
+
+		for _, v := range metadataNames {
+			if v.name == name {
+				m, ok = m|v.flag, true
+			}
+		}
+
+		if !ok {
+			return MetadataNone, fmt.Errorf("unknown file metadata value: %s", name)
+		}
+	}
+
+	return m, nil
+}
+
+func (m Metadata) String() string {
+	names := make([]string, 0, len(metadataNames))
+
+	for _, v := range metadataNames {
+		if m&v.flag != 0 {
+			names = append(names, v.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, ",")
+}
+
+// fromMetadata returns file digest with selected file metadata folded in.
+func fromMetadata(hr Hasher, e entry, m Metadata, sum []byte) ([]byte, error) {
+	if m == MetadataNone {
+		return sum, nil
+	}
+
+	var st unix.Stat_t
+
+	stat := unix.Stat
+	if e.kind != entryFile {
+		stat = unix.Lstat
+	}
+
+	if err := stat(e.name, &st); err != nil {
+		return nil, fmt.Errorf("stat %s: %w", e.name, err)
+	}
+
+	h, err := hr.New()
+	if err != nil {
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
+	}
+
+	fmt.Fprintf(h, "%x", sum)
+
+	if m&MetadataMode != 0 {
+		fmt.Fprintf(h, " mode=%o", st.Mode&^unix.S_IFMT)
+	}
+
+	if m&MetadataOwner != 0 {
+		fmt.Fprintf(h, " uid=%d gid=%d", st.Uid, st.Gid)
+	}
+
+	if m&MetadataXattrs != 0 {
+		xattrs, err := getXattrs(e.name, e.kind == entryFile)
+		if err != nil {
+			return nil, fmt.Errorf("xattrs %s: %w", e.name, err)
+		}
+
+		for _, name := range sortedKeys(xattrs) {
+			fmt.Fprintf(h, " xattr.%s=%x", name, xattrs[name])
+		}
+	}
+
+	return h.Sum(nil), nil
+}
+
+// getXattrs returns extended attributes of file, symlinks are followed only when requested.
+func getXattrs(path string, follow bool) (map[string][]byte, error) {
+	list, get := unix.Llistxattr, unix.Lgetxattr
+	if follow {
+		list, get = unix.Listxattr, unix.Getxattr
+	}
+
+	buf, err := readXattr(func(dest []byte) (int, error) { return list(path, dest) })
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte)
+
+	for _, name := range bytes.Split(buf, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+
+		val, err := readXattr(func(dest []byte) (int, error) { return get(path, string(name), dest) })
+		if err != nil {
+			if errors.Is(err, unix.ENODATA) {
+				// attribute was removed after listing
+				continue
+			}
+
+			return nil, err
+		}
+
+		out[string(name)] = val
+	}
+
+	return out, nil
+}
+
+// readXattr calls xattr syscall with buffer of required size.
+func readXattr(call func(dest []byte) (int, error)) ([]byte, error) {
+	for {
+		size, err := call(nil)
+		if err != nil {
+			if errors.Is(err, unix.ENOTSUP) {
+				// filesystem does not support extended attributes
+				return nil, nil
+			}
+
+			return nil, err //nolint: wrapcheck // error is wrapped in exported function
+		}
+
+		if size == 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size)
+
+		n, err := call(buf)
+		if errors.Is(err, unix.ERANGE) {
+			// attribute grew between calls
+			continue
+		}
+
+		if err != nil {
+			return nil, err //nolint: wrapcheck // error is wrapped in exported function
+		}
+
+		return buf[:n], nil
+	}
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,22 @@
+package hash
+
+// Options defines optional path hashing parameters.
+type Options struct {
+	// Hasher is used for file and path digests, HighwayHash is used when not set.
+	Hasher Hasher
+	// Prefix is prepended to relative file paths in manifest.
+	Prefix string
+
+	// FullHashEvery forces rehash of all files on every N-th call of incremental hasher, zero disables it.
+	FullHashEvery int
+
+	// Workers is number of files hashed concurrently, values less than one mean sequential hashing.
+	Workers int
+	// IOBudget limits file read rate in bytes per second for single path hash computation, zero disables limit.
+	IOBudget int64
+
+	Filter   Filter
+	Symlinks SymlinkPolicy
+	// Metadata selects file attributes that are hashed in addition to file content.
+	Metadata Metadata
+}
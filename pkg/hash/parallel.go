@@ -0,0 +1,81 @@
+package hash
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// hashEntries returns digests of entries, entries are hashed concurrently by `workers` goroutines,
+// digests are returned in entries order, so that aggregated digest is deterministic.
+func hashEntries(opts Options, entries []entry, lim *limiter) ([][]byte, error) {
+	sums := make([][]byte, len(entries))
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > len(entries) {
+		workers = len(entries)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		once   sync.Once
+		err    error
+		failed int32
+	)
+
+	jobs := make(chan int)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if atomic.LoadInt32(&failed) != 0 {
+					continue
+				}
+
+				sum, e := hashEntry(opts, entries[i], lim)
+				if e != nil {
+					once.Do(func() { err = e })
+					atomic.StoreInt32(&failed, 1)
+
+					continue
+				}
+
+				sums[i] = sum
+			}
+		}()
+	}
+
+	for i := range entries {
+		if atomic.LoadInt32(&failed) != 0 {
+			break
+		}
+
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sums, nil
+}
+
+// hashEntry returns entry digest with metadata folded in.
+func hashEntry(opts Options, e entry, lim *limiter) ([]byte, error) {
+	sum, err := fromEntry(opts.Hasher, e, lim)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromMetadata(opts.Hasher, e, opts.Metadata, sum)
+}
@@ -1,33 +1,213 @@
 package hash
 
 import (
+	"errors"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sort"
+
+	"golang.org/x/sys/unix"
 )
 
-func getListOfFilesFromPath(path string) ([]string, error) {
-	var files []string
+type entryKind int
+
+const (
+	// file content is hashed
+	entryFile entryKind = iota
+	// symlink target path is hashed
+	entryLink
+	// symlink target does not exist or can not be resolved (loop), symlink target path is hashed
+	entryDangling
+)
+
+// entry describes single hashed file.
+type entry struct {
+	// path inside hashed path, for followed symlinked directories it contains symlink path
+	name string
+	// slash separated path relative to hashed path, base name when hashed path is file
+	rel  string
+	kind entryKind
+	// symlink target, set only for symlink entries
+	link string
+}
+
+type dirID struct {
+	dev uint64
+	ino uint64
+}
 
-	path = filepath.Clean(path)
+type walker struct {
+	root   string
+	filter Filter
+	policy SymlinkPolicy
 
-	if err := filepath.WalkDir(path, func(file string, info fs.DirEntry, err error) error {
+	entries []entry
+}
+
+func getListOfFilesFromPath(path string, filter Filter, policy SymlinkPolicy) ([]entry, error) {
+	w := &walker{
+		root:   filepath.Clean(path),
+		filter: filter,
+		policy: policy,
+	}
+
+	// filter and symlink policy are not applied to hashed path itself
+	fi, err := os.Stat(w.root)
+	if err != nil {
+		return nil, err //nolint: wrapcheck // error is wrapped in exported function
+	}
+
+	if !fi.IsDir() {
+		return []entry{{name: filepath.ToSlash(w.root), rel: filepath.Base(w.root)}}, nil
+	}
+
+	id, err := getDirID(w.root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.walk(w.root, map[dirID]bool{id: true}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(w.entries, func(i, j int) bool {
+		return w.entries[i].name < w.entries[j].name
+	})
+
+	return w.entries, nil
+}
+
+// walk adds entries for all files inside directory,
+// ancestors contains directories on current walk path, used for symlink loop detection.
+func (w *walker) walk(dir string, ancestors map[dirID]bool) error {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// directory was removed during walk
+			return nil
+		}
+
+		return err //nolint: wrapcheck // error is wrapped in exported function
+	}
+
+	for _, de := range des {
+		file := filepath.Join(dir, de.Name())
+
+		rel, err := filepath.Rel(w.root, file)
 		if err != nil {
-			return err
+			return err //nolint: wrapcheck // error is wrapped in exported function
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		switch {
+		case de.IsDir():
+			if w.filter.skipDir(rel, de.Name()) {
+				continue
+			}
+
+			if err := w.walkDir(file, ancestors); err != nil {
+				return err
+			}
+
+		case de.Type()&fs.ModeSymlink != 0:
+			if err := w.symlink(file, rel, de.Name(), ancestors); err != nil {
+				return err
+			}
+
+		default:
+			if w.filter.skipFile(rel, de.Name()) {
+				continue
+			}
+
+			w.entries = append(w.entries, entry{name: filepath.ToSlash(file), rel: rel})
+		}
+	}
+
+	return nil
+}
+
+func (w *walker) walkDir(dir string, ancestors map[dirID]bool) error {
+	id, err := getDirID(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
 		}
 
-		if info.IsDir() {
+		return err
+	}
+
+	if ancestors[id] {
+		// symlink loop
+		return nil
+	}
+
+	ancestors[id] = true
+	defer delete(ancestors, id)
+
+	return w.walk(dir, ancestors)
+}
+
+func (w *walker) symlink(file, rel, name string, ancestors map[dirID]bool) error {
+	if w.policy == SymlinkIgnore {
+		return nil
+	}
+
+	link, err := os.Readlink(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil
 		}
 
-		files = append(files, filepath.ToSlash(file))
+		return err //nolint: wrapcheck // error is wrapped in exported function
+	}
+
+	if w.policy == SymlinkTarget {
+		if !w.filter.skipFile(rel, name) {
+			w.entries = append(w.entries, entry{name: filepath.ToSlash(file), rel: rel, kind: entryLink, link: link})
+		}
 
 		return nil
-	}); err != nil {
-		return nil, err //nolint: wrapcheck // error is wrapped in exported function
 	}
 
-	sort.Strings(files)
+	fi, err := os.Stat(file)
+
+	switch {
+	// symlink loop or path through non-directory is unresolvable same as missing target
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, unix.ELOOP), errors.Is(err, unix.ENOTDIR):
+		if !w.filter.skipFile(rel, name) {
+			w.entries = append(w.entries, entry{name: filepath.ToSlash(file), rel: rel, kind: entryDangling, link: link})
+		}
+
+		return nil
+
+	case err != nil:
+		return err //nolint: wrapcheck // error is wrapped in exported function
+
+	case fi.IsDir():
+		// symlinks to directories (e.g. kubernetes projected volume `..data`) are followed only by follow policy,
+		// otherwise directory content is hashed using its real path
+		if w.policy != SymlinkFollow || w.filter.skipDir(rel, name) {
+			return nil
+		}
+
+		return w.walkDir(file, ancestors)
+	}
+
+	if !w.filter.skipFile(rel, name) {
+		w.entries = append(w.entries, entry{name: filepath.ToSlash(file), rel: rel})
+	}
+
+	return nil
+}
+
+func getDirID(dir string) (dirID, error) {
+	var st unix.Stat_t
+
+	if err := unix.Stat(dir, &st); err != nil {
+		return dirID{}, err //nolint: wrapcheck // error is wrapped in exported function
+	}
 
-	return files, nil
+	return dirID{dev: st.Dev, ino: st.Ino}, nil //nolint: unconvert // types differ between architectures
 }
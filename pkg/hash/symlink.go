@@ -0,0 +1,53 @@
+package hash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SymlinkPolicy defines how symbolic links inside hashed path are handled.
+type SymlinkPolicy int
+
+// Available symlink policies.
+const (
+	// SymlinkContent hashes content of symlinked files, symlinks to directories are not followed.
+	SymlinkContent SymlinkPolicy = iota
+	// SymlinkTarget hashes symlink target path instead of its content.
+	SymlinkTarget
+	// SymlinkFollow hashes content of symlinked files and descends into symlinked directories,
+	// symlink loops are detected and not descended into.
+	SymlinkFollow
+	// SymlinkIgnore skips symlinks.
+	SymlinkIgnore
+)
+
+// ParseSymlinkPolicy matches symlink policy name to internal type.
+func ParseSymlinkPolicy(val string) (SymlinkPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "content":
+		return SymlinkContent, nil
+	case "target":
+		return SymlinkTarget, nil
+	case "follow":
+		return SymlinkFollow, nil
+	case "ignore":
+		return SymlinkIgnore, nil
+	}
+
+	return SymlinkContent, fmt.Errorf("unknown symlink policy value: %s", val)
+}
+
+func (p SymlinkPolicy) String() string {
+	switch p {
+	case SymlinkContent:
+		return "content"
+	case SymlinkTarget:
+		return "target"
+	case SymlinkFollow:
+		return "follow"
+	case SymlinkIgnore:
+		return "ignore"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,154 @@
+package hash
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	"golang.org/x/sys/unix"
+)
+
+// fileStat contains file attributes that change when file content or symlink target is modified.
+type fileStat struct {
+	kind entryKind
+
+	dev   uint64
+	ino   uint64
+	size  int64
+	mtime unix.Timespec
+	ctime unix.Timespec
+}
+
+type fileState struct {
+	stat fileStat
+	sum  []byte
+}
+
+// Tree computes digest of all files inside path incrementally,
+// file content is rehashed only when its (inode, size, mtime, ctime) changes.
+// Resulting digest is same as one returned by FromPathWithOptions.
+type Tree struct {
+	path string
+	opts Options
+
+	calls int
+
+	files    map[string]fileState
+	manifest string
+}
+
+// NewTree creates incremental hasher for path.
+func NewTree(path string, opts Options) *Tree {
+	if opts.Hasher == nil {
+		opts.Hasher = HighwayHash
+	}
+
+	return &Tree{
+		path:  path,
+		opts:  opts,
+		files: make(map[string]fileState),
+	}
+}
+
+// Sum returns digest of all files inside path.
+func (t *Tree) Sum() (string, error) {
+	entries, err := getListOfFilesFromPath(t.path, t.opts.Filter, t.opts.Symlinks)
+	if err != nil {
+		return "", fmt.Errorf("hash error, path '%s': %w", t.path, err)
+	}
+
+	t.calls++
+
+	full := t.opts.FullHashEvery > 0 && t.calls%t.opts.FullHashEvery == 0
+
+	files := make([]string, 0, len(entries))
+	names := make([]string, 0, len(entries))
+	state := make(map[string]fileState, len(entries))
+	sums := make(map[string][]byte, len(entries))
+
+	// entries which content must be rehashed
+	var (
+		changed      []entry
+		changedStats []fileStat
+	)
+
+	for _, e := range entries {
+		files = append(files, e.name)
+		names = append(names, path.Join(t.opts.Prefix, e.rel))
+
+		// file is stated before reading, so modification during read is detected on next call
+		st, err := statEntry(e)
+		if err != nil {
+			return "", fmt.Errorf("hash error, path '%s': %w", t.path, err)
+		}
+
+		if prev, ok := t.files[e.name]; ok && !full && prev.stat == st {
+			state[e.name] = prev
+			sums[e.name] = prev.sum
+
+			continue
+		}
+
+		changed = append(changed, e)
+		changedStats = append(changedStats, st)
+	}
+
+	changedSums, err := hashEntries(t.opts, changed, newLimiter(t.opts.IOBudget))
+	if err != nil {
+		return "", fmt.Errorf("hash error, path '%s': %w", t.path, err)
+	}
+
+	for i, e := range changed {
+		state[e.name] = fileState{stat: changedStats[i], sum: changedSums[i]}
+		sums[e.name] = changedSums[i]
+	}
+
+	manifest, sum, err := fromSums(t.opts.Hasher, files, names, sums)
+	if err != nil {
+		return "", fmt.Errorf("hash error, path '%s': %w", t.path, err)
+	}
+
+	t.files, t.manifest = state, manifest
+
+	return t.opts.Hasher.Name() + ":" + base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// statEntry returns attributes of file, symlink entries are not followed.
+func statEntry(e entry) (fileStat, error) {
+	var st unix.Stat_t
+
+	stat := unix.Stat
+	if e.kind != entryFile {
+		stat = unix.Lstat
+	}
+
+	if err := stat(e.name, &st); err != nil {
+		return fileStat{}, fmt.Errorf("stat %s: %w", e.name, err)
+	}
+
+	return fileStat{
+		kind:  e.kind,
+		dev:   st.Dev,
+		ino:   st.Ino,
+		size:  st.Size,
+		mtime: st.Mtim,
+		ctime: st.Ctim,
+	}, nil
+}
+
+// Manifest returns manifest computed by last successful Sum call,
+// it contains `<hex file hash>  <relative file path>` line per file.
+func (t *Tree) Manifest() string {
+	return t.manifest
+}
+
+// Digests returns per-file digests computed by last successful Sum call.
+func (t *Tree) Digests() Digests {
+	out := make(Digests, len(t.files))
+
+	for file, state := range t.files {
+		out[file] = state.sum
+	}
+
+	return out
+}
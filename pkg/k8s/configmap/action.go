@@ -1,87 +1,399 @@
 package configmap
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/s3rj1k/ninit/pkg/utils"
+	"github.com/s3rj1k/ninit/pkg/hash"
 )
 
-// RemoveFilesFromDir removes regular files from directory path, without recursion.
-// Function can be provided with a list of file name exception,
-// this files will not be removed.
-func (obj *Object) RemoveFilesFromDir(path string, except ...string) error {
-	path = filepath.Clean(path)
+/*
+	Files are written using same layout as kubelet uses for ConfigMap and Secret volumes:
+		- all keys are staged into new `..<timestamp>` directory, every file is fsynced;
+		- `..data_tmp` symlink pointing to new directory is created and renamed to `..data`,
+		  rename atomically switches whole key set;
+		- every key is a symlink to `..data/<key>`, symlinks are created only for new keys;
+		- files of removed keys and old `..<timestamp>` directory are removed.
+	Reader of `<base>/<key>` files never observes partially written or mixed old and new key set.
+	Files of unchanged keys are hard linked into new directory, so that their inode and mtime are preserved,
+	nothing is written when all keys are unchanged.
+*/
 
-	files, err := os.ReadDir(path)
+const (
+	// dataDirName is a symlink to directory with current object files.
+	dataDirName = "..data"
+	// dataDirTmpName is a symlink that is renamed to dataDirName.
+	dataDirTmpName = "..data_tmp"
+	// reservedPrefix is used for internal directory entries, object keys can not start with it.
+	reservedPrefix = ".."
+	// timestampDirFormat is format of directories with object files, random suffix is appended.
+	timestampDirFormat = "..2006_01_02_15_04_05."
+)
+
+func (obj *Object) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("kubernetes %s '%s/%s' event '%s', "+format,
+		append([]interface{}{obj.kind, obj.Namespace, obj.Name, obj.eventType}, a...)...)
+}
+
+func (obj *Object) infof(format string, a ...interface{}) {
+	obj.log.Infof("%s '%s/%s' event '%s', "+format,
+		append([]interface{}{obj.kind, obj.Namespace, obj.Name, obj.eventType}, a...)...)
+}
+
+func (obj *Object) debugf(format string, a ...interface{}) {
+	obj.log.Debugf("%s '%s/%s' event '%s', "+format,
+		append([]interface{}{obj.kind, obj.Namespace, obj.Name, obj.eventType}, a...)...)
+}
+
+// files returns object content as file name to file content map.
+func (obj *Object) files() (map[string][]byte, error) {
+	for k := range obj.data {
+		if k == "" || k == "." || strings.HasPrefix(k, reservedPrefix) || strings.ContainsRune(k, os.PathSeparator) {
+			return nil, obj.errorf("invalid key '%s'", k)
+		}
+	}
+
+	return obj.data, nil
+}
+
+// Write syncs files content from kubernetes config map to container local directory.
+// Destination files are compared with object data, only keys with changed content, mode or owner are written,
+// default file attributes are overridden per key by object annotations.
+// Files that are absent in object data key are also removed.
+// Returned changes contain paths of added, modified and removed files.
+func (obj *Object) Write(basePath string, defaults FileAttributes) (hash.Changes, error) {
+	basePath = filepath.Clean(basePath)
+
+	files, err := obj.files()
+	if err != nil {
+		return hash.Changes{}, err
+	}
+
+	attrs, err := obj.attributes(files, defaults)
+	if err != nil {
+		return hash.Changes{}, err
+	}
+
+	changes, unchanged, err := obj.diff(basePath, files, attrs)
+	if err != nil {
+		return hash.Changes{}, err
+	}
+
+	if changes.IsEmpty() {
+		return changes, nil
+	}
+
+	dir, err := obj.stage(basePath, files, attrs, unchanged)
 	if err != nil {
-		return fmt.Errorf("configMap '%s/%s' event '%s', cleaning path '%s' error: %w",
-			obj.Namespace, obj.Name, obj.eventType, path, err)
+		return hash.Changes{}, err
+	}
+
+	if err := obj.swap(basePath, dir); err != nil {
+		_ = os.RemoveAll(dir)
+
+		return hash.Changes{}, err
 	}
 
-	files = utils.FilterDirEntries(
-		files,
-		func(x string) bool {
-			return !utils.IsStringInSlice(x, except)
-		},
-	)
+	if err := obj.link(basePath, files); err != nil {
+		return hash.Changes{}, err
+	}
+
+	if err := obj.clean(basePath, files, filepath.Base(dir)); err != nil {
+		return hash.Changes{}, err
+	}
+
+	if err := syncDir(basePath); err != nil {
+		return hash.Changes{}, obj.errorf("syncing path '%s' error: %w", basePath, err)
+	}
+
+	return changes, nil
+}
+
+// Remove removes object files from directory path, without recursion,
+// other regular files inside directory are also removed.
+// Returned changes contain paths of removed files.
+func (obj *Object) Remove(basePath string) (hash.Changes, error) {
+	basePath = filepath.Clean(basePath)
+
+	changes, _, err := obj.diff(basePath, nil, nil)
+	if err != nil {
+		return hash.Changes{}, err
+	}
+
+	if err := obj.clean(basePath, nil, ""); err != nil {
+		return hash.Changes{}, err
+	}
+
+	if err := syncDir(basePath); err != nil {
+		return hash.Changes{}, obj.errorf("syncing path '%s' error: %w", basePath, err)
+	}
+
+	return changes, nil
+}
+
+// diff compares object files with destination files, paths of destination files
+// with unchanged content and attributes are returned by key.
+func (obj *Object) diff(basePath string, files map[string][]byte, attrs map[string]FileAttributes) (hash.Changes, map[string]string, error) {
+	var changes hash.Changes
+
+	unchanged := make(map[string]string)
+
+	for _, k := range sortedKeys(files) {
+		path := filepath.Join(basePath, k)
+
+		// symlinks are resolved, so that file of current `..<timestamp>` directory can be hard linked
+		real, err := filepath.EvalSymlinks(path)
+		if errors.Is(err, os.ErrNotExist) {
+			changes.Added = append(changes.Added, path)
 
-	for _, file := range files {
-		if !file.Type().IsRegular() {
 			continue
 		}
 
-		filePath := filepath.Join(path, file.Name())
-		obj.log.Infof("ConfigMap '%s/%s' event '%s', removing file '%s'\n", obj.Namespace, obj.Name, obj.eventType, filePath)
+		if err != nil {
+			return hash.Changes{}, nil, obj.errorf("reading file '%s' error: %w", path, err)
+		}
 
-		if err := os.Remove(filePath); err != nil {
-			return fmt.Errorf("configMap '%s/%s' event '%s', removing file '%s' error: %w",
-				obj.Namespace, obj.Name, obj.eventType, filePath, err)
+		if !isFileSynced(real, files[k], attrs[k]) {
+			changes.Modified = append(changes.Modified, path)
+
+			continue
 		}
+
+		unchanged[k] = real
 	}
 
-	return nil
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return hash.Changes{}, nil, obj.errorf("cleaning path '%s' error: %w", basePath, err)
+	}
+
+	for _, entry := range entries {
+		if _, ok := files[entry.Name()]; ok || strings.HasPrefix(entry.Name(), reservedPrefix) {
+			continue
+		}
+
+		if isStale(basePath, entry) {
+			changes.Removed = append(changes.Removed, filepath.Join(basePath, entry.Name()))
+		}
+	}
+
+	return changes, unchanged, nil
 }
 
-// Write syncs files content from kubernetes config map to container local directory.
-// No check is preformed on destination file vs source file, content is overwritten.
-// Files that are absent in object data key are also removed.
-func (obj *Object) Write(basePath string) error {
-	// https://kubernetes.io/docs/concepts/configuration/configmap/#configmap-object
-	except := make([]string, 0, len(obj.Data)+len(obj.BinaryData))
+// stage writes all files into new timestamped directory, unchanged files are hard linked.
+func (obj *Object) stage(basePath string, files map[string][]byte, attrs map[string]FileAttributes, unchanged map[string]string) (string, error) {
+	dir, err := os.MkdirTemp(basePath, time.Now().UTC().Format(timestampDirFormat))
+	if err != nil {
+		return "", obj.errorf("staging path '%s' error: %w", basePath, err)
+	}
 
-	for k := range obj.Data {
-		except = append(except, k)
+	if err := os.Chmod(dir, 0o755); err != nil { //nolint: gosec // same permissions as kubelet uses
+		_ = os.RemoveAll(dir)
+
+		return "", obj.errorf("staging path '%s' error: %w", dir, err)
 	}
 
-	for k := range obj.BinaryData {
-		except = append(except, k)
+	for _, k := range sortedKeys(files) {
+		path := filepath.Join(dir, k)
+
+		if old, ok := unchanged[k]; ok {
+			if err := os.Link(old, path); err == nil {
+				continue
+			}
+
+			// file is rewritten when hard link is not possible
+		}
+
+		obj.infof("writing file '%s'\n", path)
+
+		if err := writeFile(path, files[k], attrs[k]); err != nil {
+			_ = os.RemoveAll(dir)
+
+			return "", obj.errorf("writing file '%s' error: %w", path, err)
+		}
 	}
 
-	if err := obj.RemoveFilesFromDir(basePath, except...); err != nil {
-		return err
+	if err := syncDir(dir); err != nil {
+		_ = os.RemoveAll(dir)
+
+		return "", obj.errorf("syncing path '%s' error: %w", dir, err)
 	}
 
-	for k, v := range obj.Data {
+	return dir, nil
+}
+
+// swap atomically points `..data` symlink to directory.
+func (obj *Object) swap(basePath, dir string) error {
+	tmp := filepath.Join(basePath, dataDirTmpName)
+
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return obj.errorf("removing file '%s' error: %w", tmp, err)
+	}
+
+	if err := os.Symlink(filepath.Base(dir), tmp); err != nil {
+		return obj.errorf("creating symlink '%s' error: %w", tmp, err)
+	}
+
+	data := filepath.Join(basePath, dataDirName)
+	obj.infof("switching '%s' to '%s'\n", data, dir)
+
+	if err := os.Rename(tmp, data); err != nil {
+		_ = os.Remove(tmp)
+
+		return obj.errorf("renaming symlink '%s' error: %w", tmp, err)
+	}
+
+	return nil
+}
+
+// link creates `<key> -> ..data/<key>` symlinks, existing regular files are atomically replaced.
+func (obj *Object) link(basePath string, files map[string][]byte) error {
+	for _, k := range sortedKeys(files) {
 		path := filepath.Join(basePath, k)
-		obj.log.Infof("ConfigMap '%s/%s' event '%s', writing file '%s'\n", obj.Namespace, obj.Name, obj.eventType, path)
+		target := filepath.Join(dataDirName, k)
+
+		if v, err := os.Readlink(path); err == nil && v == target {
+			continue
+		}
+
+		obj.infof("linking file '%s' to '%s'\n", path, target)
 
-		if err := os.WriteFile(path, []byte(v), 0644); err != nil {
-			return fmt.Errorf("configMap '%s/%s' event '%s', writing file '%s' error: %w",
-				obj.Namespace, obj.Name, obj.eventType, path, err)
+		tmp := filepath.Join(basePath, reservedPrefix+k+".tmp")
+
+		if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return obj.errorf("removing file '%s' error: %w", tmp, err)
+		}
+
+		if err := os.Symlink(target, tmp); err != nil {
+			return obj.errorf("creating symlink '%s' error: %w", tmp, err)
+		}
+
+		if err := os.Rename(tmp, path); err != nil {
+			_ = os.Remove(tmp)
+
+			return obj.errorf("renaming symlink '%s' error: %w", tmp, err)
 		}
 	}
 
-	for k, v := range obj.BinaryData {
-		path := filepath.Join(basePath, k)
-		obj.log.Infof("ConfigMap '%s/%s' event '%s', writing file '%s'\n", obj.Namespace, obj.Name, obj.eventType, path)
+	return nil
+}
+
+// clean removes files of absent keys, other regular files and internal directory entries except current ones,
+// when current directory is not set `..data` symlink is removed too.
+func (obj *Object) clean(basePath string, files map[string][]byte, current string) error {
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return obj.errorf("cleaning path '%s' error: %w", basePath, err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(basePath, name)
+
+		if _, ok := files[name]; ok {
+			continue
+		}
+
+		switch {
+		case name == current:
+			continue
+
+		case name == dataDirName && current != "":
+			continue
+
+		case strings.HasPrefix(name, reservedPrefix):
+			// old timestamped directories and leftovers of interrupted writes
 
-		if err := os.WriteFile(path, v, 0644); err != nil {
-			return fmt.Errorf("configMap '%s/%s' event '%s', writing file '%s' error: %w",
-				obj.Namespace, obj.Name, obj.eventType, path, err)
+		case !isStale(basePath, entry):
+			continue
+		}
+
+		obj.infof("removing file '%s'\n", path)
+
+		if err := os.RemoveAll(path); err != nil {
+			return obj.errorf("removing file '%s' error: %w", path, err)
 		}
 	}
 
 	return nil
 }
+
+// isStale reports that directory entry is a regular file or symlink to `..data` which key is absent in object.
+func isStale(basePath string, entry os.DirEntry) bool {
+	if entry.Type().IsRegular() {
+		return true
+	}
+
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+
+	v, err := os.Readlink(filepath.Join(basePath, entry.Name()))
+
+	return err == nil && strings.HasPrefix(v, dataDirName+string(os.PathSeparator))
+}
+
+// isFileSynced reports that file content is equal to data and file has expected mode and owner.
+func isFileSynced(path string, data []byte, attrs FileAttributes) bool {
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() != int64(len(data)) || !attrs.isMatching(fi) {
+		return false
+	}
+
+	b, err := os.ReadFile(path)
+
+	return err == nil && bytes.Equal(b, data)
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+
+	for k := range files {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// writeFile writes data to file, sets its attributes and flushes it to disk.
+func writeFile(path string, data []byte, attrs FileAttributes) error {
+	// file is created without permissions for others until its mode and owner are set
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, attrs.Mode&0o700)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	return attrs.set(path)
+}
+
+// syncDir flushes directory entries to disk.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+	defer dir.Close()
+
+	return dir.Sync() //nolint: wrapcheck // error is wrapped by caller
+}
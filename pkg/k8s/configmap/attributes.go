@@ -0,0 +1,93 @@
+package configmap
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/s3rj1k/ninit/pkg/utils"
+	"github.com/s3rj1k/ninit/pkg/validate"
+)
+
+// Annotation prefixes for per key file attributes, object key is appended to prefix.
+const (
+	ModeAnnotationPrefix = "ninit.io/mode."
+	UIDAnnotationPrefix  = "ninit.io/uid."
+	GIDAnnotationPrefix  = "ninit.io/gid."
+)
+
+// FileAttributes defines mode and owner of written files,
+// negative UID or GID keeps owner of file unchanged.
+type FileAttributes struct {
+	Mode os.FileMode
+	UID  int
+	GID  int
+}
+
+// attributes returns file attributes of every key, defaults are overridden by object annotations.
+func (obj *Object) attributes(files map[string][]byte, defaults FileAttributes) (map[string]FileAttributes, error) {
+	out := make(map[string]FileAttributes, len(files))
+
+	for k := range files {
+		attrs := defaults
+
+		if val, ok := obj.Annotations[ModeAnnotationPrefix+k]; ok {
+			mode, err := utils.ParseFileMode(val)
+			if err != nil {
+				return nil, obj.errorf("annotation '%s': %w", ModeAnnotationPrefix+k, err)
+			}
+
+			attrs.Mode = mode
+		}
+
+		for _, v := range []struct {
+			prefix string
+			id     *int
+		}{
+			{UIDAnnotationPrefix, &attrs.UID},
+			{GIDAnnotationPrefix, &attrs.GID},
+		} {
+			val, ok := obj.Annotations[v.prefix+k]
+			if !ok {
+				continue
+			}
+
+			if err := validate.Uint(val); err != nil {
+				return nil, obj.errorf("annotation '%s': %w", v.prefix+k, err)
+			}
+
+			*v.id, _ = strconv.Atoi(val)
+		}
+
+		out[k] = attrs
+	}
+
+	return out, nil
+}
+
+// isMatching reports that file info has same mode and owner as attributes.
+func (attrs FileAttributes) isMatching(fi os.FileInfo) bool {
+	if fi.Mode().Perm() != attrs.Mode {
+		return false
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+
+	return (attrs.UID < 0 || int(st.Uid) == attrs.UID) && (attrs.GID < 0 || int(st.Gid) == attrs.GID)
+}
+
+// set sets file mode and owner, mode is not affected by umask.
+func (attrs FileAttributes) set(path string) error {
+	if err := os.Chmod(path, attrs.Mode); err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if attrs.UID < 0 && attrs.GID < 0 {
+		return nil
+	}
+
+	return os.Chown(path, attrs.UID, attrs.GID) //nolint: wrapcheck // error is wrapped by caller
+}
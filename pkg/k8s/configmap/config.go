@@ -1,14 +1,25 @@
 package configmap
 
 import (
+	"os"
 	"time"
 )
 
 // Config defines package configuration interface.
 type Config interface {
-	GetK8sBaseDirectory() string
-	GetK8sNamespace() string
-	GetK8sObjectName() string
+	GetK8sBindings() []Binding
 	GetPauseChannel() chan bool
 	GetWatchInterval() time.Duration
 }
+
+// Binding defines configuration interface of kubernetes object that is synced into directory.
+type Binding interface {
+	GetBaseDirectory() string
+	GetFileGID() int
+	GetFileMode() os.FileMode
+	GetFileUID() int
+	GetName() string
+	GetNamespace() string
+	GetObjectKind() Kind
+	GetObjectName() string
+}
@@ -4,57 +4,32 @@ import (
 	"reflect"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 func addEvent(log logger.Logger, obj interface{}) *Object {
-	cm, ok := obj.(*corev1.ConfigMap)
-	if !ok {
-		return nil
-	}
-
-	return &Object{
-		ConfigMap: cm,
-
-		eventType: watch.Added,
-		log:       log,
-	}
+	return newObject(log, obj, watch.Added)
 }
 
 func deleteEvent(log logger.Logger, obj interface{}) *Object {
-	cm, ok := obj.(*corev1.ConfigMap)
-	if !ok {
-		return nil
-	}
-
-	return &Object{
-		ConfigMap: cm,
-
-		eventType: watch.Deleted,
-		log:       log,
-	}
+	return newObject(log, obj, watch.Deleted)
 }
 
 func updateEvent(log logger.Logger, oldObj, newObj interface{}) *Object {
-	oldCM, ok := oldObj.(*corev1.ConfigMap)
-	if !ok {
+	prev := newObject(log, oldObj, watch.Modified)
+	if prev == nil {
 		return nil
 	}
 
-	newCM, ok := newObj.(*corev1.ConfigMap)
-	if !ok {
+	cur := newObject(log, newObj, watch.Modified)
+	if cur == nil {
 		return nil
 	}
 
-	if reflect.DeepEqual(oldCM.Data, newCM.Data) && reflect.DeepEqual(oldCM.BinaryData, newCM.BinaryData) {
+	// annotations define file attributes, so that their change also requires files update
+	if reflect.DeepEqual(prev.data, cur.data) && reflect.DeepEqual(prev.Annotations, cur.Annotations) {
 		return nil
 	}
 
-	return &Object{
-		ConfigMap: newCM,
-
-		eventType: watch.Modified,
-		log:       log,
-	}
+	return cur
 }
@@ -0,0 +1,61 @@
+package configmap
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Kind defines kubernetes object kind which content is synced to files.
+type Kind int
+
+// Available object kinds.
+const (
+	// KindConfigMap syncs ConfigMap Data and BinaryData keys.
+	KindConfigMap Kind = iota
+	// KindSecret syncs Secret Data keys.
+	KindSecret
+)
+
+// ParseKind matches object kind name to internal type.
+func ParseKind(val string) (Kind, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "configmap":
+		return KindConfigMap, nil
+	case "secret":
+		return KindSecret, nil
+	}
+
+	return KindConfigMap, fmt.Errorf("unknown object kind value: %s", val)
+}
+
+func (k Kind) String() string {
+	switch k {
+	case KindConfigMap:
+		return "ConfigMap"
+	case KindSecret:
+		return "Secret"
+	}
+
+	return "unknown"
+}
+
+// resource returns kubernetes API resource name of object kind.
+func (k Kind) resource() string {
+	if k == KindSecret {
+		return corev1.ResourceSecrets.String()
+	}
+
+	return corev1.ResourceConfigMaps.String()
+}
+
+// object returns empty kubernetes object of kind, used as informer object type.
+func (k Kind) object() runtime.Object {
+	if k == KindSecret {
+		return &corev1.Secret{}
+	}
+
+	return &corev1.ConfigMap{}
+}
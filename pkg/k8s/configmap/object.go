@@ -5,16 +5,61 @@ import (
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+// Object is a ConfigMap or Secret received with informer event.
 type Object struct {
-	*corev1.ConfigMap
+	metav1.ObjectMeta
+
+	kind Kind
+	data map[string][]byte // key -> file content
 
 	log       logger.Logger
 	eventType watch.EventType
 }
 
+// newObject converts ConfigMap or Secret to object, nil is returned for other types.
+func newObject(log logger.Logger, obj interface{}, eventType watch.EventType) *Object {
+	switch v := obj.(type) {
+	case *corev1.ConfigMap:
+		// https://kubernetes.io/docs/concepts/configuration/configmap/#configmap-object
+		data := make(map[string][]byte, len(v.Data)+len(v.BinaryData))
+
+		for k, val := range v.Data {
+			data[k] = []byte(val)
+		}
+
+		for k, val := range v.BinaryData {
+			data[k] = val
+		}
+
+		return &Object{
+			ObjectMeta: v.ObjectMeta,
+
+			kind: KindConfigMap,
+			data: data,
+
+			eventType: eventType,
+			log:       log,
+		}
+
+	case *corev1.Secret:
+		return &Object{
+			ObjectMeta: v.ObjectMeta,
+
+			kind: KindSecret,
+			data: v.Data,
+
+			eventType: eventType,
+			log:       log,
+		}
+	}
+
+	return nil
+}
+
 func (obj *Object) IsAdded() bool {
 	return obj.eventType == watch.Added
 }
@@ -28,5 +73,5 @@ func (obj *Object) IsDeleted() bool {
 }
 
 func (obj *Object) String() string {
-	return fmt.Sprintf("ConfigMap '%s/%s' got event '%s'", obj.Namespace, obj.Name, obj.eventType)
+	return fmt.Sprintf("%s '%s/%s' got event '%s'", obj.kind, obj.Namespace, obj.Name, obj.eventType)
 }
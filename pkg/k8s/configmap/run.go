@@ -7,18 +7,25 @@ import (
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 )
 
-// Run starts kubernetes ConfigMap event watch and local config update inside container.
+// Run starts kubernetes ConfigMap or Secret event watch and local config update inside container,
+// every binding has its own informer and worker.
 func Run(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger) error {
-	cmWatch, err := Watch(ctx, wg, log, c.GetK8sNamespace(), c.GetK8sObjectName(), c.GetWatchInterval())
-	if err != nil {
-		return err
-	}
+	// path watch is paused by one worker at a time
+	mu := new(sync.Mutex)
+
+	for _, b := range c.GetK8sBindings() {
+		cmWatch, err := Watch(ctx, wg, log, b.GetObjectKind(), b.GetNamespace(), b.GetObjectName(), c.GetWatchInterval())
+		if err != nil {
+			return err
+		}
 
-	wg.Add(1)
+		wg.Add(1)
 
-	log.Tracef("Starting to read channel with kubernetes object (ADDED/MODIFIED/DELETED) events\n")
+		log.Tracef("Starting to read channel with kubernetes %s '%s/%s' (ADDED/MODIFIED/DELETED) events\n",
+			b.GetObjectKind(), b.GetNamespace(), b.GetObjectName())
 
-	go worker(ctx, wg, c, cmWatch)
+		go worker(ctx, wg, c, b, mu, cmWatch)
+	}
 
 	return nil
 }
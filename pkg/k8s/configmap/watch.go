@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -21,7 +20,7 @@ const (
 	ObjectNameField = "metadata.name"
 )
 
-func Watch(ctx context.Context, wg *sync.WaitGroup, log logger.Logger, namespace, cmName string, interval time.Duration) (<-chan *Object, error) {
+func Watch(ctx context.Context, wg *sync.WaitGroup, log logger.Logger, kind Kind, namespace, name string, interval time.Duration) (<-chan *Object, error) {
 	out := make(chan *Object, 1)
 
 	restConfig, err := rest.InClusterConfig()
@@ -36,15 +35,15 @@ func Watch(ctx context.Context, wg *sync.WaitGroup, log logger.Logger, namespace
 
 	watchlist := cache.NewListWatchFromClient(
 		clientset.CoreV1().RESTClient(),
-		corev1.ResourceConfigMaps.String(),
+		kind.resource(),
 		namespace,
 		// https://github.com/kubernetes/kubernetes/issues/43299
-		fields.OneTermEqualSelector(ObjectNameField, cmName),
+		fields.OneTermEqualSelector(ObjectNameField, name),
 	)
 
 	_, controller := cache.NewInformer(
 		watchlist,
-		&corev1.ConfigMap{},
+		kind.object(),
 		interval,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
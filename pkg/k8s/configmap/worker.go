@@ -3,17 +3,27 @@ package configmap
 import (
 	"context"
 	"sync"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
 )
 
 func worker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	c Config,
+	b Binding,
+	mu *sync.Mutex,
 	cmWatch <-chan *Object,
 ) {
 	// pause path watch when write/delete operation is in progress
 	pause := c.GetPauseChannel()
 
+	attrs := FileAttributes{
+		Mode: b.GetFileMode(),
+		UID:  b.GetFileUID(),
+		GID:  b.GetFileGID(),
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -22,29 +32,46 @@ func worker(
 			return
 
 		case obj := <-cmWatch:
-			if obj.IsModified() || obj.IsAdded() {
-				obj.log.Infof("%s\n", obj.String())
+			if !obj.IsModified() && !obj.IsAdded() && !obj.IsDeleted() {
+				continue
+			}
 
-				pause <- true
+			obj.log.Infof("%s\n", obj.String())
 
-				if err := obj.Write(c.GetK8sBaseDirectory()); err != nil {
-					obj.log.Errorf("%v\n", err)
-				}
+			// pause and resume of concurrent workers must not interleave
+			mu.Lock()
 
-				pause <- false
+			setPause(ctx, pause, true)
+
+			changes, err := apply(obj, b.GetBaseDirectory(), attrs)
+			if err != nil {
+				obj.log.Errorf("%v\n", err)
+			} else {
+				obj.infof("files synced, added: %d, modified: %d, removed: %d\n",
+					len(changes.Added), len(changes.Modified), len(changes.Removed))
+				obj.debugf("changed files: %q\n", changes.All())
 			}
 
-			if obj.IsDeleted() {
-				obj.log.Infof("%s\n", obj.String())
+			setPause(ctx, pause, false)
 
-				pause <- true
+			mu.Unlock()
+		}
+	}
+}
 
-				if err := obj.RemoveFilesFromDir(c.GetK8sBaseDirectory()); err != nil {
-					obj.log.Errorf("%v\n", err)
-				}
+// setPause sends pause state, send is dropped when path watch is already stopped.
+func setPause(ctx context.Context, pause chan<- bool, v bool) {
+	select {
+	case pause <- v:
+	case <-ctx.Done():
+	}
+}
 
-				pause <- false
-			}
-		}
+// apply writes or removes object files, depending on event type.
+func apply(obj *Object, basePath string, attrs FileAttributes) (hash.Changes, error) {
+	if obj.IsDeleted() {
+		return obj.Remove(basePath)
 	}
+
+	return obj.Write(basePath, attrs)
 }
@@ -0,0 +1,46 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action defines what is done with application when its health check fails.
+type Action int
+
+// Available failure actions.
+const (
+	// None only logs check failure.
+	None Action = iota
+	// Restart restarts application, regardless of restart policy.
+	Restart
+	// Terminate stops application without restart.
+	Terminate
+)
+
+// ParseAction matches failure action name to internal type.
+func ParseAction(val string) (Action, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "none":
+		return None, nil
+	case "restart":
+		return Restart, nil
+	case "terminate":
+		return Terminate, nil
+	}
+
+	return None, fmt.Errorf("unknown failure action value: %s", val)
+}
+
+func (a Action) String() string {
+	switch a {
+	case None:
+		return "none"
+	case Restart:
+		return "restart"
+	case Terminate:
+		return "terminate"
+	}
+
+	return "unknown"
+}
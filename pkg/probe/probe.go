@@ -0,0 +1,93 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Probe checks that application is healthy.
+type Probe interface {
+	Check(ctx context.Context) error
+	String() string
+}
+
+// Parse creates probe from URL, supported schemes: 'http', 'https' and 'tcp',
+// e.g. 'http://127.0.0.1:8080/healthz' or 'tcp://127.0.0.1:80'.
+func Parse(val string) (Probe, error) {
+	u, err := url.Parse(strings.TrimSpace(val))
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe value: %s, %w", val, err)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid probe value: %s, host is empty", val)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return &httpProbe{url: u.String()}, nil
+	case "tcp":
+		if u.Port() == "" {
+			return nil, fmt.Errorf("invalid probe value: %s, port is empty", val)
+		}
+
+		return &tcpProbe{addr: u.Host}, nil
+	}
+
+	return nil, fmt.Errorf("invalid probe value: %s, expecting 'http', 'https' or 'tcp' scheme", val)
+}
+
+// httpProbe succeeds when HTTP GET request returns 2xx or 3xx status code.
+type httpProbe struct {
+	url string
+}
+
+func (p *httpProbe) String() string {
+	return fmt.Sprintf("HTTP probe '%s'", p.url)
+}
+
+func (p *httpProbe) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s: unexpected status code: %d", p, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// tcpProbe succeeds when TCP connection is established.
+type tcpProbe struct {
+	addr string
+}
+
+func (p *tcpProbe) String() string {
+	return fmt.Sprintf("TCP probe '%s'", p.addr)
+}
+
+func (p *tcpProbe) Check(ctx context.Context) error {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+
+	_ = conn.Close()
+
+	return nil
+}
@@ -1,7 +1,13 @@
 package reaper
 
+import "golang.org/x/sys/unix"
+
 // Message describes output from Run function.
 type Message struct {
 	Error   error
 	Message string
+
+	// PID and Status are set when child process was reaped.
+	PID    int
+	Status unix.WaitStatus
 }
@@ -32,6 +32,8 @@ func syscallWait() (*Message, bool) {
 		// child was reaped
 		return &Message{
 			Message: fmt.Sprintf("reaper cleanup: pid=%d, status=%+v", pid, status),
+			PID:     pid,
+			Status:  status,
 		}, false
 	}
 
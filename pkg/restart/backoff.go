@@ -0,0 +1,60 @@
+package restart
+
+import (
+	"time"
+)
+
+const backoffMultiplier = 2
+
+// Backoff computes exponentially growing delay between consecutive restarts.
+type Backoff struct {
+	// Initial is a delay before first restart.
+	Initial time.Duration
+	// Max caps delay between restarts.
+	Max time.Duration
+	// MaxRetries limits number of consecutive restarts, zero means unlimited.
+	MaxRetries int
+	// ResetWindow resets delay and retries counter when process was running at least for this duration.
+	ResetWindow time.Duration
+
+	retries int
+	delay   time.Duration
+}
+
+// Next returns delay before next restart for process that was running for `uptime` duration,
+// false is returned when retries are exhausted.
+func (b *Backoff) Next(uptime time.Duration) (time.Duration, bool) {
+	if b.ResetWindow > 0 && uptime >= b.ResetWindow {
+		b.Reset()
+	}
+
+	if b.MaxRetries > 0 && b.retries >= b.MaxRetries {
+		return 0, false
+	}
+
+	b.retries++
+
+	switch {
+	case b.delay == 0:
+		b.delay = b.Initial
+	default:
+		b.delay *= backoffMultiplier
+	}
+
+	if b.Max > 0 && b.delay > b.Max {
+		b.delay = b.Max
+	}
+
+	return b.delay, true
+}
+
+// Retries returns number of consecutive restarts since last reset.
+func (b *Backoff) Retries() int {
+	return b.retries
+}
+
+// Reset resets delay and retries counter.
+func (b *Backoff) Reset() {
+	b.retries = 0
+	b.delay = 0
+}
@@ -0,0 +1,57 @@
+package restart
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy defines when supervised process is restarted after exit.
+type Policy int
+
+// Available restart policies.
+const (
+	Never Policy = iota
+	OnFailure
+	Always
+)
+
+// Parse matches restart policy name to internal type.
+func Parse(val string) (Policy, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "never", "no":
+		return Never, nil
+	case "on-failure":
+		return OnFailure, nil
+	case "always":
+		return Always, nil
+	}
+
+	return Never, fmt.Errorf("unknown restart policy value: %s", val)
+}
+
+func (p Policy) String() string {
+	switch p {
+	case Never:
+		return "never"
+	case OnFailure:
+		return "on-failure"
+	case Always:
+		return "always"
+	}
+
+	return "unknown"
+}
+
+// IsRestartable reports whether process that exited with provided exit code must be restarted.
+func (p Policy) IsRestartable(exitCode int) bool {
+	switch p {
+	case Never:
+		return false
+	case OnFailure:
+		return exitCode != 0
+	case Always:
+		return true
+	}
+
+	return false
+}
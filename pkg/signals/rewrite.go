@@ -0,0 +1,64 @@
+package signals
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Rewrite maps received signal to forwarded signal,
+// zero value target means that signal is dropped.
+type Rewrite map[unix.Signal]unix.Signal
+
+// ParseRewrite parses comma separated list of `SOURCE:TARGET` signal pairs,
+// empty TARGET means that SOURCE signal is dropped, e.g. "TERM:QUIT,INT:TERM,WINCH:".
+func ParseRewrite(val string) (Rewrite, error) {
+	r := make(Rewrite)
+
+	for _, pair := range strings.Split(val, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		fields := strings.Split(pair, ":")
+		if len(fields) != 2 { //nolint: gomnd // signal pair
+			return nil, fmt.Errorf("invalid signal rewrite value: %s, expecting 'SOURCE:TARGET'", pair)
+		}
+
+		src, err := Parse(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := r[src]; ok {
+			return nil, fmt.Errorf("duplicate signal rewrite value: %s", fields[0])
+		}
+
+		if strings.TrimSpace(fields[1]) == "" {
+			r[src] = 0
+
+			continue
+		}
+
+		dst, err := Parse(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		r[src] = dst
+	}
+
+	return r, nil
+}
+
+// Apply returns signal that must be forwarded instead of received signal,
+// false is returned when signal must be dropped.
+func (r Rewrite) Apply(sig unix.Signal) (unix.Signal, bool) {
+	dst, ok := r[sig]
+	if !ok {
+		return sig, true
+	}
+
+	return dst, dst != 0
+}
@@ -0,0 +1,143 @@
+package snapshot
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// stagedFile is a restored file copy that is waiting to be renamed into place.
+type stagedFile struct {
+	tmp string
+	dst string
+}
+
+// stageEntry writes snapshot copy of file into temporary file inside destination directory.
+func stageEntry(src, dst string) (stagedFile, error) {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return stageLink(src, dst, fi)
+	}
+
+	// symlinked file content is restored in symlink target, so that symlink itself is kept
+	if real, err := filepath.EvalSymlinks(dst); err == nil {
+		dst = real
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil { //nolint: gosec // directory permissions are same as for newly created directories
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".ninit-")
+	if err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	f := stagedFile{tmp: out.Name(), dst: dst}
+
+	_, err = io.Copy(out, in)
+	if err == nil {
+		// content must be on disk before rename makes it visible
+		err = out.Sync()
+	}
+
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = copyAttributes(f.tmp, fi)
+	}
+
+	if err != nil {
+		_ = os.Remove(f.tmp)
+
+		return stagedFile{}, err
+	}
+
+	return f, nil
+}
+
+func stageLink(src, dst string, fi os.FileInfo) (stagedFile, error) {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil { //nolint: gosec // directory permissions are same as for newly created directories
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	// temporary file reserves unique name for symlink
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".ninit-")
+	if err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	_ = tmp.Close()
+
+	if err := os.Remove(tmp.Name()); err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if err := os.Symlink(target, tmp.Name()); err != nil {
+		return stagedFile{}, err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if err := copyOwner(tmp.Name(), fi); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return stagedFile{}, err
+	}
+
+	return stagedFile{tmp: tmp.Name(), dst: dst}, nil
+}
+
+// copyAttributes sets file mode and owner from file info.
+func copyAttributes(name string, fi os.FileInfo) error {
+	if err := os.Chmod(name, fi.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)); err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	return copyOwner(name, fi)
+}
+
+// copyOwner sets file owner from file info, ownership change is skipped when it is not permitted (init is not run as root).
+func copyOwner(name string, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	err := os.Lchown(name, int(st.Uid), int(st.Gid))
+	if errors.Is(err, unix.EPERM) {
+		return nil
+	}
+
+	return err //nolint: wrapcheck // error is wrapped by caller
+}
+
+// syncDirectory flushes directory entries, so that renamed and removed files survive crash.
+func syncDirectory(name string) error {
+	dir, err := os.Open(name)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+	defer dir.Close()
+
+	return dir.Sync() //nolint: wrapcheck // error is wrapped by caller
+}
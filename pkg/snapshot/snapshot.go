@@ -0,0 +1,248 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
+)
+
+// ErrModified is returned when path is modified while snapshot is taken.
+var ErrModified = errors.New("path modified while snapshot was taken")
+
+// Snapshot is a copy of files inside path with known digest,
+// files are selected and hashed same way as path watcher does.
+type Snapshot struct {
+	path string
+	opts hash.Options
+
+	// directory that snapshot files are relative to, parent directory when path is file
+	base string
+	// temporary directory with copies of files
+	dir string
+
+	digest  string
+	digests hash.Digests // relative file path -> file digest
+}
+
+// Take copies files inside path into temporary directory.
+func Take(path string, opts hash.Options) (*Snapshot, error) {
+	base, err := baseDirectory(path)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot error, path '%s': %w", path, err)
+	}
+
+	tree := hash.NewTree(path, opts)
+
+	digest, err := tree.Sum()
+	if err != nil {
+		return nil, fmt.Errorf("snapshot error: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "ninit-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("snapshot error, path '%s': %w", path, err)
+	}
+
+	s := &Snapshot{
+		path:    path,
+		opts:    opts,
+		base:    base,
+		dir:     dir,
+		digest:  digest,
+		digests: relativeDigests(base, tree.Digests()),
+	}
+
+	for rel := range s.digests {
+		if err := copyEntry(filepath.Join(base, rel), filepath.Join(dir, rel), opts.Symlinks); err != nil {
+			_ = s.Remove()
+
+			return nil, fmt.Errorf("snapshot error, path '%s': %w", path, err)
+		}
+	}
+
+	// path is rehashed (only files with changed attributes are read), so that partial copy is never kept
+	after, err := tree.Sum()
+	if err != nil {
+		_ = s.Remove()
+
+		return nil, fmt.Errorf("snapshot error: %w", err)
+	}
+
+	if after != digest {
+		_ = s.Remove()
+
+		return nil, fmt.Errorf("snapshot error, path '%s': %w", path, ErrModified)
+	}
+
+	return s, nil
+}
+
+// Digest returns path digest at time when snapshot was taken.
+func (s *Snapshot) Digest() string {
+	return s.digest
+}
+
+// Remove deletes snapshot files.
+func (s *Snapshot) Remove() error {
+	return os.RemoveAll(s.dir) //nolint: wrapcheck // error is logged by caller
+}
+
+// Restore brings path back to snapshot state: modified and removed files are replaced with snapshot copies
+// and files added after snapshot are deleted. All replacement files are staged next to their destination first
+// and then renamed into place, so that every file is replaced atomically and no partially written file is observed.
+func (s *Snapshot) Restore() error {
+	tree := hash.NewTree(s.path, s.opts)
+
+	if _, err := tree.Sum(); err != nil {
+		return fmt.Errorf("restore error: %w", err)
+	}
+
+	changes := hash.Diff(s.digests, relativeDigests(s.base, tree.Digests()))
+
+	staged := make([]stagedFile, 0, len(changes.Modified)+len(changes.Removed))
+
+	cleanup := func() {
+		for _, f := range staged {
+			_ = os.Remove(f.tmp)
+		}
+	}
+
+	for _, files := range [][]string{changes.Modified, changes.Removed} {
+		for _, rel := range files {
+			f, err := stageEntry(filepath.Join(s.dir, rel), filepath.Join(s.base, rel))
+			if err != nil {
+				cleanup()
+
+				return fmt.Errorf("restore error, path '%s': %w", s.path, err)
+			}
+
+			staged = append(staged, f)
+		}
+	}
+
+	dirs := make(map[string]bool)
+
+	for i, f := range staged {
+		if err := os.Rename(f.tmp, f.dst); err != nil {
+			// files that are already renamed into place are kept, remaining staged files are removed
+			staged = staged[i:]
+			cleanup()
+
+			return fmt.Errorf("restore error, path '%s': %w", s.path, err)
+		}
+
+		dirs[filepath.Dir(f.dst)] = true
+	}
+
+	for _, rel := range changes.Added {
+		name := filepath.Join(s.base, rel)
+
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("restore error, path '%s': %w", s.path, err)
+		}
+
+		dirs[filepath.Dir(name)] = true
+	}
+
+	for dir := range dirs {
+		if err := syncDirectory(dir); err != nil {
+			return fmt.Errorf("restore error, path '%s': %w", s.path, err)
+		}
+	}
+
+	return nil
+}
+
+func baseDirectory(path string) (string, error) {
+	path = filepath.Clean(path)
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if fi.IsDir() {
+		return path, nil
+	}
+
+	return filepath.Dir(path), nil
+}
+
+func relativeDigests(base string, digests hash.Digests) hash.Digests {
+	out := make(hash.Digests, len(digests))
+
+	for name, sum := range digests {
+		rel, err := filepath.Rel(base, filepath.FromSlash(name))
+		if err != nil {
+			continue
+		}
+
+		out[rel] = sum
+	}
+
+	return out
+}
+
+// isLinkCopied reports that symlink itself is copied instead of content it points to,
+// symlinks are copied when their target path is hashed or target does not exist.
+func isLinkCopied(name string, policy hash.SymlinkPolicy) bool {
+	if policy == hash.SymlinkTarget {
+		return true
+	}
+
+	_, err := os.Stat(name)
+
+	return err != nil
+}
+
+func copyEntry(src, dst string, policy hash.SymlinkPolicy) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if fi.Mode()&os.ModeSymlink != 0 && isLinkCopied(src, policy) {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err //nolint: wrapcheck // error is wrapped by caller
+		}
+
+		return os.Symlink(target, dst) //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+	defer in.Close()
+
+	fi, err = in.Stat()
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	if err := out.Close(); err != nil {
+		return err //nolint: wrapcheck // error is wrapped by caller
+	}
+
+	return copyAttributes(dst, fi)
+}
@@ -0,0 +1,118 @@
+package sysinit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"github.com/s3rj1k/ninit/pkg/utils"
+)
+
+const (
+	// changesEnvPrefix is prefix of envars with change set passed to pre-reload command.
+	changesEnvPrefix = "NINIT_"
+
+	watchPathEnv       = changesEnvPrefix + "WATCH_PATH"
+	changedFilesEnv    = changesEnvPrefix + "CHANGED_FILES"
+	addedFilesEnv      = changesEnvPrefix + "ADDED_FILES"
+	modifiedFilesEnv   = changesEnvPrefix + "MODIFIED_FILES"
+	removedFilesEnv    = changesEnvPrefix + "REMOVED_FILES"
+	changesManifestEnv = changesEnvPrefix + "CHANGES_MANIFEST"
+)
+
+// changesEnv returns change set envars,
+// change set manifest is written to temporary file that must be removed by returned cleanup function.
+func changesEnv(path string, changes hash.Changes) ([]string, func(), error) {
+	manifest, err := writeChangesManifest(changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		_ = os.Remove(manifest)
+	}
+
+	env := []string{
+		watchPathEnv + "=" + path,
+		changedFilesEnv + "=" + strings.Join(changes.All(), "\n"),
+		addedFilesEnv + "=" + strings.Join(changes.Added, "\n"),
+		modifiedFilesEnv + "=" + strings.Join(changes.Modified, "\n"),
+		removedFilesEnv + "=" + strings.Join(changes.Removed, "\n"),
+		changesManifestEnv + "=" + manifest,
+	}
+
+	return env, cleanup, nil
+}
+
+// writeChangesManifest writes one '<A|M|D><TAB><PATH>' line per changed file into temporary file.
+func writeChangesManifest(changes hash.Changes) (string, error) {
+	f, err := os.CreateTemp("", "ninit-changes-*")
+	if err != nil {
+		return "", fmt.Errorf("changes manifest error: %w", err)
+	}
+
+	var b strings.Builder
+
+	for _, v := range []struct {
+		status string
+		files  []string
+	}{
+		{"A", changes.Added},
+		{"M", changes.Modified},
+		{"D", changes.Removed},
+	} {
+		for _, file := range v.files {
+			fmt.Fprintf(&b, "%s\t%s\n", v.status, file)
+		}
+	}
+
+	_, err = f.WriteString(b.String())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+
+		return "", fmt.Errorf("changes manifest error: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// runPreReload runs chain of pre-reload commands in order with change set passed through envars,
+// chain stops on first failed command.
+func runPreReload(ctx context.Context, cmds *commands, log logger.Logger, name string, chain []*exec.Cmd, path string, changes hash.Changes) error {
+	env, cleanup, err := changesEnv(path, changes)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	for i, cmd := range chain {
+		cmd.Env = append(
+			utils.FilterStringSlice(
+				cmd.Env,
+				func(x string) bool {
+					return !strings.HasPrefix(x, changesEnvPrefix)
+				},
+			),
+			env...,
+		)
+
+		if len(chain) == 1 {
+			return runCommand(ctx, cmds, log, name, cmd)
+		}
+
+		if err := runCommand(ctx, cmds, log, fmt.Sprintf("%s #%d", name, i+1), cmd); err != nil {
+			return fmt.Errorf("command #%d '%s': %w", i+1, cmd.String(), err)
+		}
+	}
+
+	return nil
+}
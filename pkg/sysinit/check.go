@@ -0,0 +1,116 @@
+package sysinit
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"github.com/s3rj1k/ninit/pkg/probe"
+)
+
+// postCheck is running post-reload check of watch rule.
+type postCheck struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func isPostReloadCheckDefined(c Config) bool {
+	return c.GetPostReloadCheckCommandPath() != "" || c.GetPostReloadCheckProbe() != nil
+}
+
+// postReloadCheck verifies that processes survived reload and are healthy,
+// on failure rollback command is run and configured failure action is applied.
+func postReloadCheck(ctx context.Context, c Config, log logger.Logger, cmds *commands, rule *watchRule, changes hash.Changes) {
+	var err error
+
+	for attempt := 0; attempt <= c.GetPostReloadCheckRetries(); attempt++ {
+		select {
+		case <-time.After(c.GetPostReloadCheckInterval()):
+		case <-ctx.Done():
+			return
+		}
+
+		err = checkReloaded(ctx, c, log, cmds, rule)
+		if err == nil {
+			log.Infof("%s post-reload check passed\n", rule)
+
+			return
+		}
+
+		log.Warnf("%s post-reload check attempt '%d' failed: %v\n", rule, attempt+1, err)
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	log.Errorf("%s post-reload check failed: %v\n", rule, err)
+
+	cmdCtx, cancel := withTimeout(ctx, c.GetPostReloadCheckTimeout())
+	defer cancel()
+
+	if cmd := configurePostReloadExecCMD(cmdCtx, c, c.GetPostReloadRollbackCommandPath(), c.GetPostReloadRollbackCommandArgs(), log); cmd != nil {
+		log.Infof("%s running rollback command: %s\n", rule, cmd.String())
+
+		if err := runPreReload(cmdCtx, cmds, log, rule.String()+" rollback command", []*exec.Cmd{cmd}, rule.path, changes); err != nil {
+			log.Errorf("%s rollback command failed: %v\n", rule, err)
+		}
+	}
+
+	// check is cancelled by newer reload while rollback command is running
+	if ctx.Err() != nil {
+		return
+	}
+
+	for _, p := range rule.procs {
+		// PID is read once, process can exit while check is running and zero PID signals own process group
+		pid := p.pid()
+		if pid == 0 {
+			continue
+		}
+
+		switch c.GetPostReloadFailureAction() {
+		case probe.None:
+		case probe.Restart:
+			log.Infof("restarting %s, post-reload check failed\n", p)
+
+			p.requestRestart()
+			sendSignal(log, -pid, c.GetStopSignal())
+			p.escalate(log, c.GetStopTimeout())
+
+		case probe.Terminate:
+			log.Infof("terminating %s, post-reload check failed\n", p)
+
+			terminate(c, log, p)
+		}
+	}
+}
+
+// checkReloaded runs single post-reload check attempt.
+func checkReloaded(ctx context.Context, c Config, log logger.Logger, cmds *commands, rule *watchRule) error {
+	for _, p := range rule.procs {
+		if p.pid() == 0 && !p.isTerminating() {
+			return fmt.Errorf("%s is not running", p)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.GetPostReloadCheckTimeout())
+	defer cancel()
+
+	if cmd := configurePostReloadExecCMD(ctx, c, c.GetPostReloadCheckCommandPath(), c.GetPostReloadCheckCommandArgs(), log); cmd != nil {
+		if err := runCommand(ctx, cmds, log, rule.String()+" post-reload check command", cmd); err != nil {
+			return fmt.Errorf("check command '%s' failed: %w", cmd.String(), err)
+		}
+	}
+
+	if pr := c.GetPostReloadCheckProbe(); pr != nil {
+		if err := pr.Check(ctx); err != nil {
+			return err //nolint: wrapcheck // error string formed in external package is styled correctly
+		}
+	}
+
+	return nil
+}
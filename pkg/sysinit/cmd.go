@@ -6,24 +6,26 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/s3rj1k/ninit/pkg/config/command"
+	"github.com/s3rj1k/ninit/pkg/config/service"
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	"github.com/s3rj1k/ninit/pkg/utils"
 	"golang.org/x/sys/unix"
 )
 
-func configureExecCMD(ctx context.Context, c Config, _ logger.Logger) *exec.Cmd {
+func configureExecCMD(ctx context.Context, c Config, s *service.Config, _ logger.Logger) *exec.Cmd {
 	cmd := exec.CommandContext( //nolint: gosec // executing command passed from config
 		ctx,
-		c.GetCommandPath(),
-		c.GetCommandArgs()...,
+		s.GetCommandPath(),
+		s.GetCommandArgs()...,
 	)
 
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if c.GetWorkDirectory() != "" {
-		cmd.Dir = c.GetWorkDirectory()
+	if s.GetWorkDirectory() != "" {
+		cmd.Dir = s.GetWorkDirectory()
 	}
 
 	cmd.Env = utils.FilterStringSlice(
@@ -41,15 +43,26 @@ func configureExecCMD(ctx context.Context, c Config, _ logger.Logger) *exec.Cmd
 	return cmd
 }
 
-func configurePreReloadExecCMD(ctx context.Context, c Config, _ logger.Logger) *exec.Cmd {
-	if c.GetPreReloadCommandPath() == "" {
+func configurePreReloadExecCMD(ctx context.Context, c Config, path string, args []string, _ logger.Logger) *exec.Cmd {
+	return configureAuxiliaryExecCMD(ctx, c, path, args)
+}
+
+// configurePostReloadExecCMD creates post-reload check or rollback command,
+// context must be limited by post-reload check timeout.
+func configurePostReloadExecCMD(ctx context.Context, c Config, path string, args []string, _ logger.Logger) *exec.Cmd {
+	return configureAuxiliaryExecCMD(ctx, c, path, args)
+}
+
+// configureAuxiliaryExecCMD creates command that is run by init itself, nil is returned for empty path.
+func configureAuxiliaryExecCMD(ctx context.Context, c Config, path string, args []string) *exec.Cmd {
+	if path == "" {
 		return nil
 	}
 
 	cmd := exec.CommandContext( //nolint: gosec // executing command passed from config
 		ctx,
-		c.GetPreReloadCommandPath(),
-		c.GetPreReloadCommandArgs()...,
+		path,
+		args...,
 	)
 
 	if c.GetWorkDirectory() != "" {
@@ -70,3 +83,18 @@ func configurePreReloadExecCMD(ctx context.Context, c Config, _ logger.Logger) *
 
 	return cmd
 }
+
+// configurePreReloadChain creates commands from pre-reload command specs,
+// commands are created on every run as `exec.Cmd` can not be reused.
+func configurePreReloadChain(ctx context.Context, c Config, specs []*command.Config, log logger.Logger) []*exec.Cmd {
+	chain := make([]*exec.Cmd, 0, len(specs))
+
+	for _, spec := range specs {
+		// command is not created for spec without path
+		if cmd := configurePreReloadExecCMD(ctx, c, spec.GetPath(), spec.GetArgs(), log); cmd != nil {
+			chain = append(chain, cmd)
+		}
+	}
+
+	return chain
+}
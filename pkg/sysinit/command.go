@@ -0,0 +1,147 @@
+package sysinit
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/s3rj1k/ninit/pkg/reaper"
+	"golang.org/x/sys/unix"
+)
+
+// commands tracks auxiliary commands (pre-reload, post-reload check, rollback) run by init,
+// zombie reaper can collect them before `cmd.Wait`, in that case exit status is delivered through registry.
+type commands struct {
+	mu     sync.Mutex
+	exited map[int]chan unix.WaitStatus
+}
+
+func newCommands() *commands {
+	return &commands{
+		exited: make(map[int]chan unix.WaitStatus),
+	}
+}
+
+// run starts command and waits for its termination,
+// command process group is killed when context is done.
+func (r *commands) run(ctx context.Context, cmd *exec.Cmd) Result {
+	// lock is held while command is started, so that zombie reaper events
+	// can not observe new process PID before it is registered
+	r.mu.Lock()
+
+	if err := cmd.Start(); err != nil {
+		r.mu.Unlock()
+
+		return failure(err)
+	}
+
+	pid := cmd.Process.Pid
+	exited := make(chan unix.WaitStatus, 1)
+	r.exited[pid] = exited
+
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.exited, pid)
+		r.mu.Unlock()
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// whole process group is killed, so that command children do not keep output pipes open
+			if err := unix.Kill(-pid, unix.SIGKILL); err != nil {
+				_ = cmd.Process.Kill()
+			}
+		case <-done:
+		}
+	}()
+
+	err := cmd.Wait()
+
+	if ctx.Err() != nil {
+		return resultFromCmd(cmd, fmt.Errorf("process with PID '%d' killed: %w", pid, ctx.Err()))
+	}
+
+	if err == nil || !isReapedElsewhere(err) {
+		return resultFromCmd(cmd, err)
+	}
+
+	select {
+	case status := <-exited:
+		return resultFromWaitStatus(status, waitStatusError(pid, status))
+	case <-ctx.Done():
+		return failure(fmt.Errorf("process with PID '%d' killed: %w", pid, ctx.Err()))
+	}
+}
+
+// reaped delivers exit status of auxiliary command collected by zombie reaper,
+// continued status is not an exit status, so that it is not delivered.
+func (r *commands) reaped(pid int, status unix.WaitStatus) {
+	if !isExitStatus(status) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if exited, ok := r.exited[pid]; ok {
+		select {
+		case exited <- status:
+		default:
+		}
+	}
+}
+
+// forward delivers zombie reaper messages to auxiliary commands and forwards them to returned channel,
+// messages are queued, so that auxiliary commands get exit status even when receiver is busy.
+func (r *commands) forward(ctx context.Context, wg *sync.WaitGroup, in <-chan reaper.Message) <-chan reaper.Message {
+	out := make(chan reaper.Message)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		var queue []reaper.Message
+
+		for {
+			var (
+				send chan<- reaper.Message
+				next reaper.Message
+			)
+
+			if len(queue) != 0 {
+				send, next = out, queue[0]
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+
+					continue
+				}
+
+				if v.PID != 0 {
+					r.reaped(v.PID, v.Status)
+				}
+
+				queue = append(queue, v)
+
+			case send <- next:
+				queue = queue[1:]
+			}
+		}
+	}()
+
+	return out
+}
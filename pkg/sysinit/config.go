@@ -3,21 +3,53 @@ package sysinit
 import (
 	"time"
 
+	"github.com/s3rj1k/ninit/pkg/config/rule"
+	"github.com/s3rj1k/ninit/pkg/config/service"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/probe"
+	"github.com/s3rj1k/ninit/pkg/restart"
+	"github.com/s3rj1k/ninit/pkg/signals"
+	"github.com/s3rj1k/ninit/pkg/watcher"
 	"golang.org/x/sys/unix"
 )
 
 // Config defines package configuration interface.
 type Config interface {
-	GetCommandArgs() []string
-	GetCommandPath() string
 	GetEnvPrefix() string
 	GetPauseChannel() chan bool
-	GetReloadSignal() unix.Signal
 	GetReloadSignalToPGID() bool
+	GetRestartBackoff() time.Duration
+	GetRestartBackoffMax() time.Duration
+	GetRestartMaxRetries() int
+	GetRestartPolicy() restart.Policy
+	GetRestartResetWindow() time.Duration
+	GetServices() []*service.Config
+	GetSignalRewrite() signals.Rewrite
 	GetSignalToDirectChildOnly() bool
+	GetStopSignal() unix.Signal
+	GetStrictPID1() bool
+	GetStopTimeout() time.Duration
+	GetWatchDebounce() time.Duration
+	GetWatchDebounceMaxWait() time.Duration
+	GetWatchFilter() hash.Filter
+	GetWatchFullHashEvery() int
+	GetWatchHasher() hash.Hasher
+	GetWatchHashIOBudget() int64
+	GetWatchHashWorkers() int
 	GetWatchInterval() time.Duration
-	GetWatchPath() string
+	GetWatchMode() watcher.Mode
+	GetWatchRules() []*rule.Config
+	GetWatchSymlinks() hash.SymlinkPolicy
 	GetWorkDirectory() string
-	GetPreReloadCommandArgs() []string
-	GetPreReloadCommandPath() string
+	GetPostReloadCheckCommandArgs() []string
+	GetPostReloadCheckCommandPath() string
+	GetPostReloadCheckInterval() time.Duration
+	GetPostReloadCheckProbe() probe.Probe
+	GetPostReloadCheckRetries() int
+	GetPostReloadCheckTimeout() time.Duration
+	GetPostReloadFailureAction() probe.Action
+	GetPostReloadRollbackCommandArgs() []string
+	GetPostReloadRollbackCommandPath() string
+	GetPreReloadRestore() bool
+	GetPreReloadTimeout() time.Duration
 }
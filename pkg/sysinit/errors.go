@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"syscall"
 )
 
 func GetErrorMessage(err error) string {
@@ -14,6 +15,10 @@ func GetErrorMessage(err error) string {
 	var exitErr *exec.ExitError
 
 	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return fmt.Sprintf("command terminated by signal: '%v', %v", status.Signal(), err)
+		}
+
 		return fmt.Sprintf("command exited with code: %d, %v", exitErr.ExitCode(), err)
 	}
 
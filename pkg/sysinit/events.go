@@ -1,8 +1,9 @@
 package sysinit
 
 import (
+	"context"
 	"os"
-	"os/exec"
+	"sync"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	"github.com/s3rj1k/ninit/pkg/reaper"
@@ -10,7 +11,7 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-func signalEvent(c Config, log logger.Logger, sig os.Signal, cmd *exec.Cmd) {
+func signalEvent(c Config, log logger.Logger, sig os.Signal, procs []*process) {
 	if sig == nil {
 		return
 	}
@@ -20,17 +21,54 @@ func signalEvent(c Config, log logger.Logger, sig os.Signal, cmd *exec.Cmd) {
 		return
 	}
 
-	pid := -cmd.Process.Pid
-	if c.GetSignalToDirectChildOnly() {
-		pid = cmd.Process.Pid
+	// termination is detected by received signal, not by rewritten one
+	isTermination := isTerminationSignal(c, signal)
+
+	// processes are stopped with stop signal, unless received signal has explicit rewrite rule
+	_, isRewritten := c.GetSignalRewrite()[signal]
+	isStop := isTermination && !isRewritten
+
+	signal, ok = c.GetSignalRewrite().Apply(signal)
+	if !ok {
+		// termination request dropped by rewrite rule does not stop processes
+		log.Debugf("'%v' signal is dropped by rewrite rule\n", sig)
+
+		return
+	}
+
+	if isStop {
+		signal = c.GetStopSignal()
 	}
 
-	sendSignal(log, pid, signal)
+	for _, proc := range procs {
+		if isTermination {
+			// process must not be restarted after termination request
+			proc.terminate()
+		}
+
+		pid := proc.pid()
+		if pid == 0 {
+			log.Debugf("'%v' signal is not forwarded to %s, no running process\n", sig, proc)
+
+			continue
+		}
 
-	log.Debugf("sent '%v' signal to PID '%d'\n", sig, -cmd.Process.Pid) // can be very verbose
+		// stop signal is always sent to process group, same as on critical service exit
+		if !c.GetSignalToDirectChildOnly() || isStop {
+			pid = -pid
+		}
+
+		sendSignal(log, pid, signal)
+
+		log.Debugf("sent '%v' signal to PID '%d'\n", signal, pid) // can be very verbose
+
+		if isTermination {
+			proc.escalate(log, c.GetStopTimeout())
+		}
+	}
 }
 
-func watcherEvent(c Config, log logger.Logger, v watcher.Message, cmd, preReloadCmd *exec.Cmd) {
+func watcherEvent(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger, wc *workerConfig, v watcher.Message, rule *watchRule) {
 	if v.Error != nil {
 		log.Errorf("%v\n", v.Error)
 	}
@@ -39,29 +77,45 @@ func watcherEvent(c Config, log logger.Logger, v watcher.Message, cmd, preReload
 		log.Infof("%v\n", v.Message)
 	}
 
-	if v.IsChanged {
-		pid := cmd.Process.Pid
-		if c.GetReloadSignalToPGID() {
-			pid = -cmd.Process.Pid
+	if !v.IsChanged {
+		// path state at watch start is the one processes were started with
+		if v.Digest != "" && isRestoreEnabled(c, rule) {
+			takeSnapshot(ctx, wg, wc, rule, v.Digest)
 		}
 
-		if preReloadCmd != nil {
-			log.Debugf("pre-reload command defined: %s\n", preReloadCmd.String())
+		return
+	}
 
-			if err := preReloadCmd.Run(); err != nil {
-				log.Errorf("failed to send '%v' signal, pre-reload command failed: %v\n", c.GetReloadSignal(), err)
+	if rule.restored != "" && rule.restored == v.Digest {
+		rule.restored = ""
 
-				return
-			}
-		}
+		log.Infof("%s restored files detected, reload is not needed\n", rule)
 
-		sendSignal(log, pid, c.GetReloadSignal())
+		return
+	}
+
+	if rule.restoring {
+		log.Debugf("%s last-known-good files are being restored, change is ignored\n", rule)
+
+		return
+	}
+
+	rule.restored = ""
+
+	log.Debugf("%s changed files: %q\n", rule, v.Changes.All())
+
+	if rule.reloading {
+		queueReload(rule, v)
 
-		log.Infof("sent '%v' signal to PID '%d'\n", c.GetReloadSignal(), pid)
+		log.Debugf("%s pre-reload command is running, change is queued\n", rule)
+
+		return
 	}
+
+	startReload(ctx, wg, c, log, wc, rule, v)
 }
 
-func reaperEvent(_ Config, log logger.Logger, v reaper.Message) {
+func reaperEvent(_ Config, log logger.Logger, v reaper.Message, procs []*process) {
 	if v.Error != nil {
 		log.Errorf("%v\n", v.Error)
 	}
@@ -69,4 +123,14 @@ func reaperEvent(_ Config, log logger.Logger, v reaper.Message) {
 	if v.Message != "" {
 		log.Infof("%v\n", v.Message)
 	}
+
+	if v.PID == 0 {
+		return
+	}
+
+	for _, proc := range procs {
+		if v.PID == proc.pid() {
+			proc.reaped(v.Status)
+		}
+	}
 }
@@ -0,0 +1,83 @@
+package sysinit
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+)
+
+// maxOutputLineLength limits buffered command output line, longer lines are logged in parts.
+const maxOutputLineLength = 4096
+
+// outputWriter logs command output line by line.
+type outputWriter struct {
+	log    logger.Logger
+	prefix string
+
+	buf []byte
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.line(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) >= maxOutputLineLength {
+		w.flush()
+	}
+
+	return len(p), nil
+}
+
+// flush logs incomplete last line.
+func (w *outputWriter) flush() {
+	if len(w.buf) != 0 {
+		w.line(w.buf)
+	}
+
+	w.buf = nil
+}
+
+func (w *outputWriter) line(b []byte) {
+	w.log.Infof("%s: %s\n", w.prefix, bytes.TrimRight(b, "\r"))
+}
+
+// runCommand runs auxiliary command with its stdout and stderr logged line by line,
+// command exit code and run duration are logged when it finishes.
+func runCommand(ctx context.Context, cmds *commands, log logger.Logger, name string, cmd *exec.Cmd) error {
+	stdout := &outputWriter{log: log, prefix: name + " stdout"}
+	stderr := &outputWriter{log: log, prefix: name + " stderr"}
+
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	start := time.Now()
+	res := cmds.run(ctx, cmd)
+
+	stdout.flush()
+	stderr.flush()
+
+	if cmd.Process == nil {
+		return res.Error
+	}
+
+	if res.Error != nil {
+		log.Errorf("%s with PID '%d' failed, exit code '%d', duration '%v'\n",
+			name, cmd.Process.Pid, res.ExitCode, time.Since(start).Round(time.Millisecond))
+	} else {
+		log.Infof("%s with PID '%d' finished, exit code '%d', duration '%v'\n",
+			name, cmd.Process.Pid, res.ExitCode, time.Since(start).Round(time.Millisecond))
+	}
+
+	return res.Error
+}
@@ -0,0 +1,80 @@
+package sysinit
+
+import (
+	"context"
+	"sync"
+)
+
+// pauseRequest changes watch rule pause state, done is closed when path watcher received resulting state.
+type pauseRequest struct {
+	paused bool
+	done   chan struct{}
+}
+
+// setPaused changes path watcher pause state and waits until watcher received it,
+// false is returned when context is done first.
+func (r *watchRule) setPaused(ctx context.Context, paused bool) bool {
+	if r.pause == nil {
+		return true
+	}
+
+	done := make(chan struct{})
+
+	select {
+	case r.pause <- pauseRequest{paused: paused, done: done}:
+	case <-ctx.Done():
+		return false
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// mergePause combines global and watch rule pause states into path watcher pause channel,
+// watcher is paused while any of states is set. Inputs are always read, even when watcher is busy.
+func mergePause(ctx context.Context, wg *sync.WaitGroup, global <-chan bool, local <-chan pauseRequest, out chan<- bool) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		var (
+			isGlobal, isLocal, delivered bool
+			waiters                      []chan struct{}
+		)
+
+		for {
+			want := isGlobal || isLocal
+
+			// pending state is offered to watcher only when it differs from delivered one
+			var send chan<- bool
+			if want != delivered {
+				send = out
+			} else {
+				for _, done := range waiters {
+					close(done)
+				}
+
+				waiters = nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case isGlobal = <-global:
+
+			case req := <-local:
+				isLocal = req.paused
+				waiters = append(waiters, req.done)
+
+			case send <- want:
+				delivered = want
+			}
+		}
+	}()
+}
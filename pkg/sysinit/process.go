@@ -0,0 +1,172 @@
+package sysinit
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/config/service"
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"golang.org/x/sys/unix"
+)
+
+// process holds supervised service state, service command is replaced on every restart.
+type process struct {
+	svc *service.Config
+
+	cmd *exec.Cmd
+	mu  sync.RWMutex
+
+	// kills process group when it does not exit in time after termination request
+	killTimer *time.Timer
+
+	// zombie reaper can collect process before `cmd.Wait`,
+	// in that case exit status is delivered through this channel
+	exited chan unix.WaitStatus
+
+	// closed when termination was requested, no restarts are done after that
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// process is restarted regardless of restart policy, set by failed post-reload check
+	restartRequested bool
+}
+
+func newProcess(svc *service.Config) *process {
+	return &process{
+		svc:    svc,
+		exited: make(chan unix.WaitStatus, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// String returns human readable service name.
+func (p *process) String() string {
+	if p.svc.GetName() == "" {
+		return "process"
+	}
+
+	return fmt.Sprintf("service '%s'", p.svc.GetName())
+}
+
+// get returns running command or nil when there is no running process.
+func (p *process) get() *exec.Cmd {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.cmd
+}
+
+// start starts command and stores it as running process, lock is held while command is started
+// so that zombie reaper events can not observe new process PID before it is stored.
+func (p *process) start(cmd *exec.Cmd) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := cmd.Start(); err != nil {
+		return err //nolint: wrapcheck // error message wrapping is done by `GetErrorMessage(err error) string`
+	}
+
+	p.cmd = cmd
+	p.restartRequested = false
+
+	return nil
+}
+
+func (p *process) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cmd = nil
+	p.stopKillTimer()
+}
+
+// stopKillTimer stops pending kill of process group, so that it can not kill restarted process
+// or process with reused PID, lock must be held by caller.
+func (p *process) stopKillTimer() {
+	if p.killTimer != nil {
+		p.killTimer.Stop()
+		p.killTimer = nil
+	}
+}
+
+// pid returns PID of running process or zero when there is no running process.
+func (p *process) pid() int {
+	cmd := p.get()
+	if cmd == nil || cmd.Process == nil {
+		return 0
+	}
+
+	return cmd.Process.Pid
+}
+
+// reaped delivers exit status of process collected by zombie reaper,
+// continued status is not an exit status, so that it is not delivered.
+func (p *process) reaped(status unix.WaitStatus) {
+	if !isExitStatus(status) {
+		return
+	}
+
+	p.mu.Lock()
+	p.stopKillTimer()
+	p.mu.Unlock()
+
+	select {
+	case p.exited <- status:
+	default:
+	}
+}
+
+// requestRestart marks running process to be restarted after it exits, regardless of restart policy.
+func (p *process) requestRestart() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.restartRequested = true
+}
+
+func (p *process) isRestartRequested() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.restartRequested
+}
+
+func (p *process) terminate() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p *process) terminating() <-chan struct{} {
+	return p.stop
+}
+
+func (p *process) isTerminating() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// escalate schedules SIGKILL for whole process group
+// when it does not exit during timeout after termination request.
+func (p *process) escalate(log logger.Logger, timeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if timeout == 0 || p.cmd == nil || p.cmd.Process == nil || p.killTimer != nil {
+		return
+	}
+
+	pgid := p.cmd.Process.Pid
+
+	log.Debugf("process group '%d' will be killed if it does not exit in '%v'\n", pgid, timeout)
+
+	p.killTimer = time.AfterFunc(timeout, func() {
+		killProcessGroup(log, pgid)
+	})
+}
@@ -0,0 +1,174 @@
+package sysinit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"github.com/s3rj1k/ninit/pkg/watcher"
+)
+
+// reloadResult contains pre-reload command result for watch rule path change.
+type reloadResult struct {
+	rule *watchRule
+	msg  watcher.Message
+	err  error
+}
+
+// withTimeout returns context with timeout, zero timeout disables it.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func isAnyRunning(procs []*process) bool {
+	for _, proc := range procs {
+		if proc.pid() != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queueReload remembers path change that is detected while pre-reload command is running,
+// consecutive changes are merged, so that only latest path state is validated.
+func queueReload(rule *watchRule, v watcher.Message) {
+	if rule.queued == nil {
+		rule.queued = &v
+
+		return
+	}
+
+	rule.queued.Changes = rule.queued.Changes.Merge(v.Changes)
+	rule.queued.Digest = v.Digest
+}
+
+// startReload runs pre-reload command in background, its result is handled by worker as reload event,
+// reload signal is sent right away when pre-reload command is not defined.
+func startReload(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger, wc *workerConfig, rule *watchRule, v watcher.Message) {
+	if !isAnyRunning(rule.procs) {
+		log.Warnf("'%v' signal is not sent, %s has no running process\n", rule.signal, rule)
+
+		return
+	}
+
+	if len(rule.preReload) == 0 {
+		reload(ctx, wg, c, log, wc.cmds, rule, v)
+
+		return
+	}
+
+	log.Debugf("%s pre-reload commands defined: %q\n", rule, rule.preReload)
+
+	rule.reloading = true
+
+	wg.Add(1)
+
+	// pre-reload command is run in background, so that signals and zombies are handled while it is running
+	go func() {
+		defer wg.Done()
+
+		cmdCtx, cancel := withTimeout(ctx, c.GetPreReloadTimeout())
+		defer cancel()
+
+		chain := configurePreReloadChain(cmdCtx, c, rule.preReload, log)
+
+		err := runPreReload(cmdCtx, wc.cmds, log, rule.String()+" pre-reload command", chain, rule.path, v.Changes)
+
+		select {
+		case wc.reloads <- reloadResult{rule: rule, msg: v, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func reloadEvent(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger, wc *workerConfig, v reloadResult) {
+	rule := v.rule
+	rule.reloading = false
+
+	if v.err != nil {
+		log.Errorf("failed to send '%v' signal, %s pre-reload command failed: %v\n", rule.signal, rule, v.err)
+
+		if isRestoreEnabled(c, rule) {
+			startRestore(ctx, wg, log, wc, rule)
+		}
+	} else {
+		if isRestoreEnabled(c, rule) {
+			takeSnapshot(ctx, wg, wc, rule, v.msg.Digest)
+		}
+
+		reload(ctx, wg, c, log, wc.cmds, rule, v.msg)
+	}
+
+	if rule.queued == nil {
+		return
+	}
+
+	next := *rule.queued
+	rule.queued = nil
+
+	if rule.restored != "" {
+		log.Infof("%s queued change is discarded, last-known-good files are restored\n", rule)
+
+		return
+	}
+
+	startReload(ctx, wg, c, log, wc, rule, next)
+}
+
+// reload sends reload signal to watch rule processes and starts post-reload check.
+func reload(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger, cmds *commands, rule *watchRule, v watcher.Message) {
+	var sent bool
+
+	for _, proc := range rule.procs {
+		// PID is read once, process can exit between reads and zero PID signals own process group
+		pid := proc.pid()
+		if pid == 0 {
+			log.Warnf("'%v' signal is not sent to %s, no running process\n", rule.signal, proc)
+
+			continue
+		}
+
+		if rule.signalToPGID {
+			pid = -pid
+		}
+
+		sendSignal(log, pid, rule.signal)
+
+		log.Infof("sent '%v' signal to PID '%d'\n", rule.signal, pid)
+
+		sent = true
+	}
+
+	if !sent || !isPostReloadCheckDefined(c) {
+		return
+	}
+
+	// check of previous reload is outdated, only one check per watch rule is running
+	prev := rule.check
+
+	checkCtx, cancel := context.WithCancel(ctx)
+	rule.check = &postCheck{cancel: cancel, done: make(chan struct{})}
+
+	done := rule.check.done
+
+	wg.Add(1)
+
+	// check is done in background, so that signals and zombies are handled while it is running
+	go func() {
+		defer wg.Done()
+		defer close(done)
+
+		if prev != nil {
+			prev.cancel()
+			<-prev.done
+		}
+
+		postReloadCheck(checkCtx, c, log, cmds, rule, v.Changes)
+	}()
+}
@@ -0,0 +1,163 @@
+package sysinit
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"github.com/s3rj1k/ninit/pkg/snapshot"
+)
+
+// isRestoreEnabled reports that rule path files are restored on pre-reload command failure.
+func isRestoreEnabled(c Config, rule *watchRule) bool {
+	return c.GetPreReloadRestore() && len(rule.preReload) != 0
+}
+
+// errSnapshotChanged is returned when path changed after its digest was validated.
+var errSnapshotChanged = errors.New("path changed after validation")
+
+// snapshotResult contains snapshot taken for watch rule.
+type snapshotResult struct {
+	rule     *watchRule
+	seq      uint64
+	snapshot *snapshot.Snapshot
+	err      error
+}
+
+// takeSnapshot copies rule path in background, its result is handled by worker as snapshot event,
+// new snapshot is kept only when path still has validated digest.
+func takeSnapshot(ctx context.Context, wg *sync.WaitGroup, wc *workerConfig, rule *watchRule, digest string) {
+	rule.snapshotSeq++
+
+	seq := rule.snapshotSeq
+
+	wg.Add(1)
+
+	// whole path is copied and hashed, so that signals and zombies are handled meanwhile
+	go func() {
+		defer wg.Done()
+
+		s, err := snapshot.Take(rule.path, rule.opts.HashOptions())
+		if err == nil && s.Digest() != digest {
+			_ = s.Remove()
+			s, err = nil, errSnapshotChanged
+		}
+
+		select {
+		case wc.snapshots <- snapshotResult{rule: rule, seq: seq, snapshot: s, err: err}:
+		case <-ctx.Done():
+			if s != nil {
+				_ = s.Remove()
+			}
+		}
+	}()
+}
+
+// snapshotEvent replaces last-known-good snapshot of rule path with taken one,
+// outdated snapshot is dropped when newer one is requested or files are being restored.
+func snapshotEvent(log logger.Logger, v snapshotResult) {
+	rule := v.rule
+
+	if v.err != nil {
+		log.Warnf("%s last-known-good snapshot is not updated: %v\n", rule, v.err)
+
+		return
+	}
+
+	s := v.snapshot
+
+	if v.seq != rule.snapshotSeq || rule.restoring {
+		log.Debugf("%s outdated snapshot is dropped, digest: %s\n", rule, s.Digest())
+
+		if err := s.Remove(); err != nil {
+			log.Warnf("%s snapshot remove error: %v\n", rule, err)
+		}
+
+		return
+	}
+
+	if rule.snapshot != nil {
+		if err := rule.snapshot.Remove(); err != nil {
+			log.Warnf("%s snapshot remove error: %v\n", rule, err)
+		}
+	}
+
+	rule.snapshot = s
+
+	log.Debugf("%s last-known-good snapshot updated, digest: %s\n", rule, s.Digest())
+}
+
+// restoreResult contains result of snapshot restore for watch rule.
+type restoreResult struct {
+	rule   *watchRule
+	digest string
+	err    error
+}
+
+// startRestore brings rule path back to last-known-good snapshot in background, its result is handled by worker
+// as restore event. Restored digest is remembered, so that path change caused by restore does not trigger reload.
+func startRestore(ctx context.Context, wg *sync.WaitGroup, log logger.Logger, wc *workerConfig, rule *watchRule) {
+	if rule.snapshot == nil {
+		log.Warnf("%s has no last-known-good snapshot, files are not restored\n", rule)
+
+		return
+	}
+
+	s := rule.snapshot
+
+	rule.restoring = true
+	rule.restored = s.Digest()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		// partially restored path must not be hashed by watcher, otherwise its digest triggers reload
+		if !rule.setPaused(ctx, true) {
+			return
+		}
+
+		err := s.Restore()
+
+		// result is handled before watcher is resumed, so that changes reported after resume are not ignored
+		select {
+		case wc.restores <- restoreResult{rule: rule, digest: s.Digest(), err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		rule.setPaused(ctx, false)
+	}()
+}
+
+func restoreEvent(log logger.Logger, v restoreResult) {
+	rule := v.rule
+	rule.restoring = false
+
+	if v.err != nil {
+		rule.restored = ""
+
+		log.Errorf("%s last-known-good files restore failed: %v\n", rule, v.err)
+
+		return
+	}
+
+	log.Infof("%s last-known-good files restored, digest: %s\n", rule, v.digest)
+}
+
+// removeSnapshots deletes snapshot files of all watch rules.
+func removeSnapshots(log logger.Logger, rules []*watchRule) {
+	for _, rule := range rules {
+		if rule.snapshot == nil {
+			continue
+		}
+
+		if err := rule.snapshot.Remove(); err != nil {
+			log.Warnf("%s snapshot remove error: %v\n", rule, err)
+		}
+
+		rule.snapshot = nil
+	}
+}
@@ -0,0 +1,77 @@
+package sysinit
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// FailureExitCode is returned when supervised process was not started or its state is unknown.
+	FailureExitCode = 1
+
+	// signalExitCodeBase is added to signal number when process was terminated by signal,
+	// same convention as used by shells, tini and dumb-init.
+	signalExitCodeBase = 128
+)
+
+// Result describes termination state of supervised process.
+type Result struct {
+	Error error
+
+	// ExitCode contains process exit code or 128+signal when process was terminated by signal.
+	ExitCode int
+	// Signal contains signal that terminated process, zero when process exited normally.
+	Signal unix.Signal
+}
+
+func failure(err error) Result {
+	return Result{
+		Error:    err,
+		ExitCode: FailureExitCode,
+	}
+}
+
+func resultFromWaitStatus(status unix.WaitStatus, err error) Result {
+	if status.Signaled() {
+		return Result{
+			Error:    err,
+			ExitCode: signalExitCodeBase + int(status.Signal()),
+			Signal:   status.Signal(),
+		}
+	}
+
+	if status.Exited() {
+		return Result{
+			Error:    err,
+			ExitCode: status.ExitStatus(),
+		}
+	}
+
+	return failure(err)
+}
+
+// isExitStatus reports that wait status is exit of process, not a stop or continue of it.
+func isExitStatus(status unix.WaitStatus) bool {
+	return status.Exited() || status.Signaled()
+}
+
+func resultFromCmd(cmd *exec.Cmd, err error) Result {
+	if cmd.ProcessState == nil {
+		return failure(err)
+	}
+
+	status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok {
+		return failure(err)
+	}
+
+	return resultFromWaitStatus(unix.WaitStatus(status), err)
+}
+
+// isReapedElsewhere reports that process wait failed because process was already collected by zombie reaper.
+func isReapedElsewhere(err error) bool {
+	return errors.Is(err, unix.ECHILD)
+}
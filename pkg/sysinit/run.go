@@ -4,22 +4,26 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"sync"
+	"time"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	"github.com/s3rj1k/ninit/pkg/reaper"
+	"github.com/s3rj1k/ninit/pkg/restart"
 	"github.com/s3rj1k/ninit/pkg/signals"
-	"github.com/s3rj1k/ninit/pkg/watcher"
 	"golang.org/x/sys/unix"
 )
 
 // Run starts and waits for termination of system init process
-// with provided config, it will forward signals to child process,
+// with provided config, it will forward signals to child processes,
 // reap zombies, send reload signal on config chage.
-func Run(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger) error {
-	if os.Getpid() != 1 {
-		return fmt.Errorf("expecting to be run as PID 1")
+// Processes are restarted according to configured restart policy.
+// Returned result contains exit code that should be used as init process exit code.
+func Run(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger) Result {
+	if err := checkPID1(c, log); err != nil {
+		return failure(err)
 	}
 
 	sigs := make(chan os.Signal, 1)
@@ -31,32 +35,187 @@ func Run(ctx context.Context, wg *sync.WaitGroup, c Config, log logger.Logger) e
 	)
 	defer signal.Reset()
 
-	cmd := configureExecCMD(ctx, c, log)
-	preReloadCmd := configurePreReloadExecCMD(ctx, c, log)
-
-	if err := cmd.Start(); err != nil {
-		return err //nolint: wrapcheck // error message wrapping is done by `GetErrorMessage(err error) string`
+	procs := make([]*process, 0, len(c.GetServices()))
+	for _, s := range c.GetServices() {
+		procs = append(procs, newProcess(s))
 	}
 
-	log.Infof("started process '%v' with PID '%d'\n", cmd.String(), cmd.Process.Pid)
-
-	watch := watcher.Path(ctx, wg, c.GetWatchPath(), c.GetWatchInterval(), c.GetPauseChannel())
-	reap := reaper.Run(ctx, wg)
+	rules := watchRules(c, procs)
+	watchEvents := watch(ctx, wg, c, rules)
+	cmds := newCommands()
+	reap := cmds.forward(ctx, wg, reaper.Run(ctx, wg))
 
 	wg.Add(1)
 
 	go worker(ctx, wg, c, log,
 		&workerConfig{
-			cmd:          cmd,
-			preReloadCmd: preReloadCmd,
-			sigs:         sigs,
-			watch:        watch,
-			reap:         reap,
+			procs: procs,
+			rules: rules,
+			cmds:  cmds,
+			sigs:  sigs,
+			watch: watchEvents,
+			reap:  reap,
+
+			reloads:   make(chan reloadResult),
+			restores:  make(chan restoreResult),
+			snapshots: make(chan snapshotResult),
 		},
 	)
 
+	return superviseAll(ctx, c, log, procs)
+}
+
+// superviseAll runs all processes and waits for their termination,
+// when critical process terminates all other processes are terminated too.
+// Result of first terminated critical process is returned,
+// otherwise result of last terminated process.
+func superviseAll(ctx context.Context, c Config, log logger.Logger, procs []*process) Result {
+	type processResult struct {
+		proc *process
+		res  Result
+	}
+
+	results := make(chan processResult, len(procs))
+
+	for _, p := range procs {
+		go func(p *process) {
+			results <- processResult{
+				proc: p,
+				res:  supervise(ctx, c, log, p),
+			}
+		}(p)
+	}
+
+	var (
+		res      Result
+		shutdown bool
+	)
+
+	for range procs {
+		v := <-results
+
+		if shutdown {
+			continue
+		}
+
+		res = v.res
+
+		if !v.proc.svc.GetCritical() {
+			continue
+		}
+
+		shutdown = true
+
+		for _, p := range procs {
+			if p == v.proc || p.isTerminating() {
+				continue
+			}
+
+			log.Infof("terminating %s, critical %s has finished\n", p, v.proc)
+
+			terminate(c, log, p)
+		}
+	}
+
+	return res
+}
+
+// terminate stops process without restart.
+func terminate(c Config, log logger.Logger, p *process) {
+	p.terminate()
+
+	if pid := p.pid(); pid != 0 {
+		sendSignal(log, -pid, c.GetStopSignal())
+		p.escalate(log, c.GetStopTimeout())
+	}
+}
+
+func supervise(ctx context.Context, c Config, log logger.Logger, p *process) Result {
+	backoff := &restart.Backoff{
+		Initial:     c.GetRestartBackoff(),
+		Max:         c.GetRestartBackoffMax(),
+		MaxRetries:  c.GetRestartMaxRetries(),
+		ResetWindow: c.GetRestartResetWindow(),
+	}
+
+	for {
+		res, uptime := start(ctx, c, log, p)
+
+		if p.isTerminating() || (!p.isRestartRequested() && !c.GetRestartPolicy().IsRestartable(res.ExitCode)) {
+			return res
+		}
+
+		delay, ok := backoff.Next(uptime)
+		if !ok {
+			log.Warnf("%s is not restarted, maximum number of restarts '%d' reached\n", p, c.GetRestartMaxRetries())
+
+			return res
+		}
+
+		if res.Error != nil {
+			log.Errorf("%v\n", GetErrorMessage(res.Error))
+		}
+
+		log.Infof("restarting %s in '%v', restart policy '%s', attempt '%d'\n", p, delay, c.GetRestartPolicy(), backoff.Retries())
+
+		select {
+		case <-time.After(delay):
+		case <-p.terminating():
+			return res
+		case <-ctx.Done():
+			return res
+		}
+	}
+}
+
+// start runs command and waits for its termination, process uptime is returned with result.
+func start(ctx context.Context, c Config, log logger.Logger, p *process) (Result, time.Duration) {
+	// drop exit status of previous process, if any
+	select {
+	case <-p.exited:
+	default:
+	}
+
+	cmd := configureExecCMD(ctx, c, p.svc, log)
+
+	if err := p.start(cmd); err != nil {
+		return failure(err), 0
+	}
+	defer p.reset()
+
+	t1 := time.Now()
+
+	log.Infof("started process '%v' with PID '%d'\n", cmd.String(), cmd.Process.Pid)
+
+	res := wait(ctx, cmd, p.exited)
+	log.Infof("finished process '%v' with PID '%d', exit code '%d'\n", cmd.String(), cmd.Process.Pid, res.ExitCode)
+
+	return res, time.Since(t1)
+}
+
+func wait(ctx context.Context, cmd *exec.Cmd, exited <-chan unix.WaitStatus) Result {
 	err := cmd.Wait()
-	log.Infof("finished process '%v' with PID '%d'\n", cmd.String(), cmd.Process.Pid)
+	if err == nil || !isReapedElsewhere(err) {
+		return resultFromCmd(cmd, err)
+	}
+
+	select {
+	case status := <-exited:
+		return resultFromWaitStatus(status, waitStatusError(cmd.Process.Pid, status))
+
+	case <-ctx.Done():
+		return failure(err)
+	}
+}
+
+// waitStatusError returns error for process that was collected by zombie reaper with non-zero exit status.
+func waitStatusError(pid int, status unix.WaitStatus) error {
+	switch {
+	case status.Signaled():
+		return fmt.Errorf("process with PID '%d' terminated by signal: '%v'", pid, status.Signal())
+	case status.Exited() && status.ExitStatus() != 0:
+		return fmt.Errorf("process with PID '%d' exited with code: %d", pid, status.ExitStatus())
+	}
 
-	return err //nolint: wrapcheck // error message wrapping is done by `GetErrorMessage(err error) string`
+	return nil
 }
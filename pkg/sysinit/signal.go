@@ -2,7 +2,6 @@ package sysinit
 
 import (
 	"errors"
-	"time"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	"golang.org/x/sys/unix"
@@ -17,10 +16,21 @@ func sendSignal(log logger.Logger, pid int, sig unix.Signal) {
 			log.Warnf("%v\n", err)
 		}
 	}
+}
+
+// isTerminationSignal reports whether signal requests process termination.
+func isTerminationSignal(c Config, sig unix.Signal) bool {
+	return sig == unix.SIGINT || sig == unix.SIGTERM || sig == c.GetStopSignal()
+}
 
-	if sig == unix.SIGINT || sig == unix.SIGTERM {
-		// lets sleep here for a bit to allow
-		// application finish writing to stdout/stderr
-		time.Sleep(1 * time.Second)
+// killProcessGroup sends SIGKILL to process group when it still has running members.
+func killProcessGroup(log logger.Logger, pgid int) {
+	if err := unix.Kill(-pgid, 0); err != nil {
+		// process group has already exited
+		return
 	}
+
+	log.Warnf("process group '%d' did not exit in time after termination request, sending '%v' signal\n", pgid, unix.SIGKILL)
+
+	sendSignal(log, -pgid, unix.SIGKILL)
 }
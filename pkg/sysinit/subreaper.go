@@ -0,0 +1,31 @@
+package sysinit
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/s3rj1k/ninit/pkg/log/logger"
+	"golang.org/x/sys/unix"
+)
+
+// checkPID1 verifies that init process is run as PID 1,
+// otherwise (when strict check is disabled) process is marked as child subreaper,
+// so that orphaned descendants are reparented to it and can be reaped.
+// https://man7.org/linux/man-pages/man2/prctl.2.html
+func checkPID1(c Config, log logger.Logger) error {
+	if os.Getpid() == 1 {
+		return nil
+	}
+
+	if c.GetStrictPID1() {
+		return fmt.Errorf("expecting to be run as PID 1")
+	}
+
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("not running as PID 1, unable to register as child subreaper: %w", err)
+	}
+
+	log.Infof("not running as PID 1, registered as child subreaper\n")
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package sysinit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/config/command"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/snapshot"
+	"github.com/s3rj1k/ninit/pkg/watcher"
+	"golang.org/x/sys/unix"
+)
+
+// watchRule binds watch path to reload action.
+type watchRule struct {
+	name string // empty for service watch path
+	path string
+
+	interval time.Duration
+	opts     watcher.Options
+
+	signal       unix.Signal
+	signalToPGID bool
+
+	// pre-reload commands run in order before sending signal
+	preReload []*command.Config
+
+	// last-known-good copy of path files, restored when pre-reload command fails
+	snapshot *snapshot.Snapshot
+	// sequence number of latest requested snapshot, results of older requests are dropped
+	snapshotSeq uint64
+	// digest of restored snapshot, path change to this digest does not trigger reload
+	restored string
+	// snapshot is being restored in background, path changes detected meanwhile are ignored
+	restoring bool
+
+	// pauses path watcher of this rule only, nil when watcher is not running
+	pause chan pauseRequest
+
+	// pre-reload command is running, path changes detected meanwhile are queued
+	reloading bool
+	queued    *watcher.Message
+
+	// post-reload check of latest reload, nil when check was never started
+	check *postCheck
+
+	// processes that receive reload signal
+	procs []*process
+}
+
+func (r *watchRule) String() string {
+	if r.name == "" {
+		return "watch path '" + r.path + "'"
+	}
+
+	return "watch rule '" + r.name + "'"
+}
+
+// watchEvent binds watcher message to watch rule which path changed.
+type watchEvent struct {
+	rule *watchRule
+	msg  watcher.Message
+}
+
+// watchRules returns watch rules for services watch paths and for configured watch rules.
+func watchRules(c Config, procs []*process) []*watchRule {
+	rules := make([]*watchRule, 0, len(procs)+len(c.GetWatchRules()))
+
+	for _, p := range procs {
+		if strings.TrimSpace(p.svc.GetWatchPath()) == "" {
+			continue
+		}
+
+		rules = append(rules, &watchRule{
+			path:         p.svc.GetWatchPath(),
+			interval:     c.GetWatchInterval(),
+			opts:         watchOptions(c, p.svc.GetWatchMetadata()),
+			signal:       p.svc.GetReloadSignal(),
+			signalToPGID: c.GetReloadSignalToPGID(),
+			preReload:    p.svc.GetPreReloadCommands(),
+			procs:        []*process{p},
+		})
+	}
+
+	for _, r := range c.GetWatchRules() {
+		wr := &watchRule{
+			name:         r.GetName(),
+			path:         r.GetPath(),
+			interval:     r.GetInterval(),
+			opts:         watchOptions(c, r.GetMetadata()),
+			signal:       r.GetSignal(),
+			signalToPGID: r.GetSignalToPGID(),
+			preReload:    r.GetPreReloadCommands(),
+		}
+
+		for _, p := range procs {
+			if r.GetService() == "" || r.GetService() == p.svc.GetName() {
+				wr.procs = append(wr.procs, p)
+			}
+		}
+
+		rules = append(rules, wr)
+	}
+
+	return rules
+}
+
+// watchOptions returns path watcher options from config, metadata is defined per watch rule.
+func watchOptions(c Config, metadata hash.Metadata) watcher.Options {
+	return watcher.Options{
+		Mode:          c.GetWatchMode(),
+		FullHashEvery: c.GetWatchFullHashEvery(),
+		Hasher:        c.GetWatchHasher(),
+		HashWorkers:   c.GetWatchHashWorkers(),
+		HashIOBudget:  c.GetWatchHashIOBudget(),
+		Filter:        c.GetWatchFilter(),
+		Symlinks:      c.GetWatchSymlinks(),
+		Metadata:      metadata,
+
+		Debounce:        c.GetWatchDebounce(),
+		DebounceMaxWait: c.GetWatchDebounceMaxWait(),
+	}
+}
+
+// watch starts path watcher for every watch rule,
+// messages from all watchers are multiplexed into single channel.
+// Every watcher is paused by global pause channel and by its own watch rule pause requests.
+func watch(ctx context.Context, wg *sync.WaitGroup, c Config, rules []*watchRule) <-chan watchEvent {
+	out := make(chan watchEvent, 1)
+
+	active := make([]*watchRule, 0, len(rules))
+	inputs := make([]chan bool, 0, len(rules))
+
+	for _, r := range rules {
+		// unbuffered, so that delivered pause state is already applied by watcher
+		in := make(chan bool)
+
+		ch := watcher.Path(ctx, wg, r.path, r.interval, in, r.opts)
+		if ch == nil {
+			continue
+		}
+
+		r.pause = make(chan pauseRequest)
+
+		active = append(active, r)
+		inputs = append(inputs, in)
+
+		wg.Add(1)
+
+		go forward(ctx, wg, r, ch, out)
+	}
+
+	pauses := broadcast(ctx, wg, c.GetPauseChannel(), len(active))
+
+	for i, r := range active {
+		mergePause(ctx, wg, pauses[i], r.pause, inputs[i])
+	}
+
+	return out
+}
+
+func forward(ctx context.Context, wg *sync.WaitGroup, r *watchRule, in <-chan watcher.Message, out chan<- watchEvent) {
+	defer wg.Done()
+
+	for v := range in {
+		select {
+		case out <- watchEvent{rule: r, msg: v}:
+		case <-ctx.Done():
+			// keep draining until watcher closes channel
+		}
+	}
+}
+
+// broadcast fans out pause channel values to `n` watchers,
+// pause channel is always read, even when there are no watchers.
+func broadcast(ctx context.Context, wg *sync.WaitGroup, in <-chan bool, n int) []<-chan bool {
+	outs := make([]chan bool, n)
+	res := make([]<-chan bool, n)
+
+	for i := range outs {
+		outs[i] = make(chan bool, 1)
+		res[i] = outs[i]
+	}
+
+	wg.Add(1)
+
+	go func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case v := <-in:
+				for _, out := range outs {
+					select {
+					case out <- v:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}(ctx, wg)
+
+	return res
+}
@@ -3,21 +3,23 @@ package sysinit
 import (
 	"context"
 	"os"
-	"os/exec"
 	"sync"
 
 	"github.com/s3rj1k/ninit/pkg/log/logger"
 	"github.com/s3rj1k/ninit/pkg/reaper"
-	"github.com/s3rj1k/ninit/pkg/watcher"
 )
 
 type workerConfig struct {
-	cmd          *exec.Cmd
-	preReloadCmd *exec.Cmd
-
-	sigs  <-chan os.Signal
-	watch <-chan watcher.Message
-	reap  <-chan reaper.Message
+	procs []*process
+	rules []*watchRule
+	cmds  *commands
+
+	sigs      <-chan os.Signal
+	watch     <-chan watchEvent
+	reap      <-chan reaper.Message
+	reloads   chan reloadResult
+	restores  chan restoreResult
+	snapshots chan snapshotResult
 }
 
 func worker(
@@ -30,18 +32,28 @@ func worker(
 	for {
 		select {
 		case <-ctx.Done():
+			removeSnapshots(log, wc.rules)
 			wg.Done()
 
 			return
 
 		case sig := <-wc.sigs:
-			signalEvent(c, log, sig, wc.cmd)
+			signalEvent(c, log, sig, wc.procs)
 
 		case v := <-wc.watch:
-			watcherEvent(c, log, v, wc.cmd, wc.preReloadCmd)
+			watcherEvent(ctx, wg, c, log, wc, v.msg, v.rule)
+
+		case v := <-wc.reloads:
+			reloadEvent(ctx, wg, c, log, wc, v)
+
+		case v := <-wc.restores:
+			restoreEvent(log, v)
+
+		case v := <-wc.snapshots:
+			snapshotEvent(log, v)
 
 		case v := <-wc.reap:
-			reaperEvent(c, log, v)
+			reaperEvent(c, log, v, wc.procs)
 		}
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"strconv"
 )
 
 const (
@@ -31,6 +32,16 @@ func GetMode(path string) (fs.FileMode, error) {
 	return info.Mode(), nil
 }
 
+// ParseFileMode returns file permission bits parsed from octal value, for example '0644'.
+func ParseFileMode(val string) (os.FileMode, error) {
+	v, err := strconv.ParseUint(val, 8, 32)
+	if err != nil || v > 0o777 {
+		return 0, fmt.Errorf("invalid file mode value '%s', must be octal permission bits (0000-0777)", val)
+	}
+
+	return os.FileMode(v), nil
+}
+
 // IsExecOwner returns true when filemode has exec owner bit set.
 func IsExecOwner(mode os.FileMode) bool {
 	return mode&ownerBit != 0
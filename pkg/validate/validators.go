@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/s3rj1k/ninit/pkg/glob"
+	"github.com/s3rj1k/ninit/pkg/hash"
+	"github.com/s3rj1k/ninit/pkg/probe"
+	"github.com/s3rj1k/ninit/pkg/restart"
 	"github.com/s3rj1k/ninit/pkg/signals"
 	"github.com/s3rj1k/ninit/pkg/utils"
+	"github.com/s3rj1k/ninit/pkg/watcher"
 	"golang.org/x/sys/unix"
 )
 
@@ -114,6 +120,19 @@ func Directory(path string) error {
 	return nil
 }
 
+// AbsoluteDirectory validate that path is valid directory and is absolute.
+func AbsoluteDirectory(path string) error {
+	if err := Directory(path); err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("path '%s' is not absolute", path)
+	}
+
+	return nil
+}
+
 // Duration validate that value is parsable `time.Duration`.
 func Duration(val string) error {
 	t, err := time.ParseDuration(val)
@@ -124,6 +143,22 @@ func Duration(val string) error {
 	return nil
 }
 
+// Uint validate that value is parsable unsigned integer.
+func Uint(val string) error {
+	if _, err := strconv.ParseUint(val, 10, 31); err != nil {
+		return fmt.Errorf("invalid unsigned integer value '%s'", val)
+	}
+
+	return nil
+}
+
+// FileMode validate that value is octal file permission bits, for example '0644'.
+func FileMode(val string) error {
+	_, err := utils.ParseFileMode(val)
+
+	return err //nolint: wrapcheck // error string formed in external package is styled correctly
+}
+
 // Bool validate that value is valid Bool (true/false).
 func Bool(val string) error {
 	if strings.EqualFold(val, "true") || strings.EqualFold(val, "false") {
@@ -143,6 +178,96 @@ func Signal(val string) error {
 	return nil
 }
 
+// SignalRewrite validate that value is valid signal rewrite table.
+func SignalRewrite(val string) error {
+	_, err := signals.ParseRewrite(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// RestartPolicy validate that value is valid restart policy name.
+func RestartPolicy(val string) error {
+	_, err := restart.Parse(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// WatchMode validate that value is valid watch mode name.
+func WatchMode(val string) error {
+	_, err := watcher.ParseMode(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// SymlinkPolicy validate that value is valid symlink policy name.
+func SymlinkPolicy(val string) error {
+	_, err := hash.ParseSymlinkPolicy(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// Hasher validate that value is valid hash algorithm name.
+func Hasher(val string) error {
+	_, err := hash.ParseHasher(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// Metadata validate that value is valid list of file metadata names.
+func Metadata(val string) error {
+	_, err := hash.ParseMetadata(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// Probe validate that value is valid probe URL.
+func Probe(val string) error {
+	_, err := probe.Parse(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// FailureAction validate that value is valid failure action name.
+func FailureAction(val string) error {
+	_, err := probe.ParseAction(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
+// Globs validate that value is valid comma separated list of glob patterns.
+func Globs(val string) error {
+	_, err := glob.ParseList(val)
+	if err != nil {
+		return err //nolint: wrapcheck // error string formed in external package is styled correctly
+	}
+
+	return nil
+}
+
 // DNSLabel validate that value is valid DNS label based on RFC 1123.
 //  * https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#dns-label-names
 //  * https://tools.ietf.org/html/rfc1123
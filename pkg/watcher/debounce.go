@@ -0,0 +1,59 @@
+package watcher
+
+import "time"
+
+// debouncer delays change reporting until path is stable for settle window,
+// continuous changes are reported after max wait is reached.
+type debouncer struct {
+	window  time.Duration
+	maxWait time.Duration
+
+	// first change time of pending change, zero when no change is pending
+	since time.Time
+	timer <-chan time.Time
+}
+
+func (d *debouncer) pending() bool {
+	return !d.since.IsZero()
+}
+
+// changed registers detected change and reports whether it must be emitted immediately,
+// otherwise settle timer is (re)armed.
+func (d *debouncer) changed(now time.Time) bool {
+	if d.window <= 0 {
+		return true
+	}
+
+	if !d.pending() {
+		d.since = now
+	}
+
+	wait := d.window
+
+	if d.maxWait > 0 {
+		left := d.since.Add(d.maxWait).Sub(now)
+		if left <= 0 {
+			return true
+		}
+
+		if left < wait {
+			wait = left
+		}
+	}
+
+	d.timer = time.After(wait)
+
+	return false
+}
+
+// retry re-arms settle timer for pending change, so that it is checked again after failed settle check.
+func (d *debouncer) retry() {
+	if d.pending() && d.window > 0 {
+		d.timer = time.After(d.window)
+	}
+}
+
+func (d *debouncer) reset() {
+	d.since = time.Time{}
+	d.timer = nil
+}
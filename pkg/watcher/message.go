@@ -3,6 +3,8 @@ package watcher
 import (
 	"fmt"
 	"time"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
 )
 
 // Message describes output from Path function.
@@ -10,6 +12,11 @@ type Message struct {
 	Error     error
 	Message   string
 	IsChanged bool
+
+	// Changes contains lists of added, modified and removed files, set only when IsChanged is true.
+	Changes hash.Changes
+	// Digest contains path digest after change or initial path digest when watch is started.
+	Digest string
 }
 
 func paused(path string) Message {
@@ -24,10 +31,20 @@ func resumed(path string) Message {
 	}
 }
 
-func change(path string, delta time.Duration) Message {
+func started(path, digest string) Message {
+	return Message{
+		Message: fmt.Sprintf("path '%s' watch started, digest: %s", path, digest),
+		Digest:  digest,
+	}
+}
+
+func change(path string, delta time.Duration, changes hash.Changes, digest string) Message {
 	return Message{
 		IsChanged: true,
-		Message:   fmt.Sprintf("path '%s' change detected (%v)", path, delta),
+		Message: fmt.Sprintf("path '%s' change detected (%v), added: %d, modified: %d, removed: %d, digest: %s",
+			path, delta, len(changes.Added), len(changes.Modified), len(changes.Removed), digest),
+		Changes: changes,
+		Digest:  digest,
 	}
 }
 
@@ -42,3 +59,15 @@ func shutdown(path string) Message {
 		Message: fmt.Sprintf("path '%s' watch is shutting down", path),
 	}
 }
+
+func notifyError(path string, err error) Message {
+	return Message{
+		Error: fmt.Errorf("inotify watch error, path '%s', falling back to polling: %w", path, err),
+	}
+}
+
+func notifyFallback(path string) Message {
+	return Message{
+		Message: fmt.Sprintf("path '%s' inotify watch lost or event queue overflow, falling back to polling", path),
+	}
+}
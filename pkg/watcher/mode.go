@@ -0,0 +1,47 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode defines how path changes are detected.
+type Mode int
+
+// Available watch modes.
+const (
+	// Poll recomputes path hash on every watch interval tick.
+	Poll Mode = iota
+	// Inotify recomputes path hash only when inotify event is received,
+	// watcher falls back to polling on inotify event queue overflow.
+	Inotify
+	// Hybrid recomputes path hash on inotify event and on every watch interval tick.
+	Hybrid
+)
+
+// ParseMode matches watch mode name to internal type.
+func ParseMode(val string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "poll":
+		return Poll, nil
+	case "inotify":
+		return Inotify, nil
+	case "hybrid":
+		return Hybrid, nil
+	}
+
+	return Poll, fmt.Errorf("unknown watch mode value: %s", val)
+}
+
+func (m Mode) String() string {
+	switch m {
+	case Poll:
+		return "poll"
+	case Inotify:
+		return "inotify"
+	case Hybrid:
+		return "hybrid"
+	}
+
+	return "unknown"
+}
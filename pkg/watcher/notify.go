@@ -0,0 +1,213 @@
+package watcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// inotify events that can signal path content change.
+	notifyMask = unix.IN_ATTRIB | unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE | unix.IN_DELETE_SELF |
+		unix.IN_MODIFY | unix.IN_MOVE_SELF | unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DONT_FOLLOW
+
+	notifyBufferSize = 64 * (unix.SizeofInotifyEvent + unix.NAME_MAX + 1)
+)
+
+/*
+	Kubernetes projected volumes (ConfigMap, Secret) are updated using atomic symlink swap:
+		- new data is written to `..<timestamp>` directory;
+		- `..data_tmp` symlink is created pointing to new directory and renamed to `..data`;
+		- old `..<timestamp>` directory is removed.
+	Files inside volume are symlinks to `..data/<file>`, so their own inotify watches never fire.
+	Watching directories (not files) catches `..data` rename and new directories are watched on creation.
+*/
+
+// notifier recursively watches path using inotify and reports that something changed inside it.
+type notifier struct {
+	// `file.Fd()` is not used as it switches file descriptor to blocking mode
+	fd   int
+	file *os.File
+
+	// root directory, its watch loss stops notifier
+	root string
+	// new directories are watched only for recursive notifier
+	recursive bool
+
+	watches map[int]string // watch descriptor -> directory path
+	mu      sync.Mutex
+
+	events   chan struct{}
+	overflow chan struct{}
+}
+
+func newNotifier(wg *sync.WaitGroup, path string) (*notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init error: %w", err)
+	}
+
+	n := &notifier{
+		// non-blocking file descriptor is used with runtime poller, so `Close` unblocks pending `Read`
+		fd:       fd,
+		file:     os.NewFile(uintptr(fd), "inotify"),
+		watches:  make(map[int]string),
+		events:   make(chan struct{}, 1),
+		overflow: make(chan struct{}, 1),
+	}
+
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		_ = n.file.Close()
+
+		return nil, fmt.Errorf("inotify watch error: %w", err)
+	}
+
+	if info.IsDir() {
+		// symlink to directory is resolved, walk does not follow it and no watches are added otherwise
+		path, err = filepath.EvalSymlinks(path)
+		if err == nil {
+			n.root, n.recursive = path, true
+			err = n.addRecursive(path)
+		}
+	} else {
+		// file can be replaced by rename or be a symlink, so its parent directory is watched
+		n.root = filepath.Dir(path)
+		err = n.add(n.root)
+	}
+
+	if err != nil {
+		_ = n.file.Close()
+
+		return nil, err
+	}
+
+	wg.Add(1)
+
+	go n.read(wg)
+
+	return n, nil
+}
+
+// Close stops notifier.
+func (n *notifier) Close() error {
+	return n.file.Close()
+}
+
+func (n *notifier) add(path string) error {
+	wd, err := unix.InotifyAddWatch(n.fd, path, notifyMask|unix.IN_ONLYDIR)
+	if err != nil {
+		return fmt.Errorf("inotify add watch error, path '%s': %w", path, err)
+	}
+
+	n.mu.Lock()
+	n.watches[wd] = path
+	n.mu.Unlock()
+
+	return nil
+}
+
+func (n *notifier) addRecursive(path string) error {
+	return filepath.WalkDir(path, func(dir string, info fs.DirEntry, err error) error { //nolint: wrapcheck // error is wrapped in `add`
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// directory was removed during walk
+				return nil
+			}
+
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		return n.add(dir)
+	})
+}
+
+func (n *notifier) read(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	buf := make([]byte, notifyBufferSize)
+
+	for {
+		l, err := n.file.Read(buf)
+		if err != nil {
+			// notifier was closed
+			return
+		}
+
+		if !n.handle(buf[:l]) {
+			notify(n.overflow)
+
+			return
+		}
+
+		notify(n.events)
+	}
+}
+
+// handle parses inotify events, watches are added for new directories,
+// false is returned when notifier can not be trusted anymore (queue overflow or lost root watch).
+func (n *notifier) handle(buf []byte) bool {
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset])) //nolint: gosec // inotify event is read from kernel provided buffer
+
+		nameStart := offset + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(event.Len)
+		offset = nameEnd
+
+		if event.Mask&unix.IN_Q_OVERFLOW != 0 {
+			return false
+		}
+
+		n.mu.Lock()
+		dir, ok := n.watches[int(event.Wd)]
+		n.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		if event.Mask&unix.IN_IGNORED != 0 {
+			n.mu.Lock()
+			delete(n.watches, int(event.Wd))
+			n.mu.Unlock()
+
+			if dir == n.root {
+				return false
+			}
+
+			continue
+		}
+
+		if !n.recursive || event.Mask&unix.IN_ISDIR == 0 || event.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) == 0 {
+			continue
+		}
+
+		name := string(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"))
+
+		// new directory, for example `..<timestamp>` directory of projected volume
+		_ = n.addRecursive(filepath.Join(dir, name))
+	}
+
+	return true
+}
+
+// notify sends non-blocking notification, pending notifications are coalesced.
+func notify(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
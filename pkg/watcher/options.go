@@ -0,0 +1,51 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
+)
+
+// Options defines optional path watcher parameters.
+type Options struct {
+	Mode Mode
+
+	// FullHashEvery forces rehash of all files on every N-th hash computation,
+	// otherwise only files with changed (inode, size, mtime, ctime) are rehashed,
+	// zero disables forced rehash.
+	FullHashEvery int
+
+	// Hasher is used for path digest, HighwayHash is used when not set.
+	Hasher hash.Hasher
+	// HashWorkers is number of files hashed concurrently.
+	HashWorkers int
+	// HashIOBudget limits file read rate in bytes per second for single path hash computation, zero disables limit.
+	HashIOBudget int64
+
+	// Filter selects files inside watched path that are hashed.
+	Filter hash.Filter
+	// Symlinks defines how symlinks inside watched path are hashed.
+	Symlinks hash.SymlinkPolicy
+	// Metadata selects file attributes that are hashed in addition to file content.
+	Metadata hash.Metadata
+
+	// Debounce delays change message until path is stable for this duration,
+	// zero disables debounce.
+	Debounce time.Duration
+	// DebounceMaxWait limits how long change message is delayed by continuous changes,
+	// zero disables limit.
+	DebounceMaxWait time.Duration
+}
+
+// HashOptions returns options used for watched path digest computation.
+func (o Options) HashOptions() hash.Options {
+	return hash.Options{
+		Hasher:        o.Hasher,
+		FullHashEvery: o.FullHashEvery,
+		Workers:       o.HashWorkers,
+		IOBudget:      o.HashIOBudget,
+		Filter:        o.Filter,
+		Symlinks:      o.Symlinks,
+		Metadata:      o.Metadata,
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/s3rj1k/ninit/pkg/hash"
 )
 
 /*
@@ -18,8 +20,9 @@ import (
 	It'll be slower than the aforementioned approach but it covers more ground.
 */
 
-// Path runs changes watcher for specified path using fast recursive file hashing.
-func Path(ctx context.Context, wg *sync.WaitGroup, path string, interval time.Duration, pause <-chan bool) <-chan Message {
+// Path runs changes watcher for specified path using fast recursive file hashing,
+// hashing is triggered by interval ticks and (or) inotify events depending on watch mode.
+func Path(ctx context.Context, wg *sync.WaitGroup, path string, interval time.Duration, pause <-chan bool, opts Options) <-chan Message {
 	if strings.TrimSpace(path) == "" || interval == 0 {
 		return nil
 	}
@@ -34,6 +37,12 @@ func Path(ctx context.Context, wg *sync.WaitGroup, path string, interval time.Du
 			interval: interval,
 			path:     path,
 			pause:    pause,
+			mode:     opts.Mode,
+			tree:     hash.NewTree(path, opts.HashOptions()),
+			debounce: &debouncer{
+				window:  opts.Debounce,
+				maxWait: opts.DebounceMaxWait,
+			},
 		},
 	)
 
@@ -13,6 +13,12 @@ type workerConfig struct {
 	pause    <-chan bool
 	path     string
 	interval time.Duration
+	mode     Mode
+
+	// keeps per-file hash state between ticks
+	tree *hash.Tree
+
+	debounce *debouncer
 }
 
 func worker(ctx context.Context, wg *sync.WaitGroup, wc *workerConfig) {
@@ -26,9 +32,75 @@ func worker(ctx context.Context, wg *sync.WaitGroup, wc *workerConfig) {
 		wg.Done()
 	}(wg, wc.ch, ticker)
 
-	initialHash, err := hash.FromPath(wc.path)
+	initialHash, err := wc.tree.Sum()
 	if err != nil {
 		wc.ch <- hashError(wc.path, err)
+	} else {
+		wc.ch <- started(wc.path, initialHash)
+	}
+
+	digests := wc.tree.Digests()
+
+	var (
+		tick     <-chan time.Time
+		events   <-chan struct{}
+		overflow <-chan struct{}
+	)
+
+	if n := startNotifier(wg, wc); n != nil {
+		defer n.Close()
+
+		events, overflow = n.events, n.overflow
+
+		if wc.mode == Hybrid {
+			tick = ticker.C
+		}
+	} else {
+		tick = ticker.C
+	}
+
+	// last observed hash, differs from initial hash while change is pending
+	lastHash := initialHash
+
+	// settled is true when debounce settle window passed without new changes
+	check := func(settled bool) {
+		t1 := time.Now()
+
+		currentHash, err := wc.tree.Sum()
+		if err != nil {
+			wc.ch <- hashError(wc.path, err)
+
+			// fired settle timer is not rearmed by following checks, pending change would be never reported
+			if settled {
+				wc.debounce.retry()
+			}
+
+			return
+		}
+
+		t2 := time.Now()
+
+		switch {
+		case currentHash != lastHash:
+			lastHash = currentHash
+
+			if !wc.debounce.changed(t2) {
+				return
+			}
+		case !settled:
+			return
+		}
+
+		wc.debounce.reset()
+
+		// changes can be reverted while waiting for path to settle
+		if currentHash != initialHash {
+			currentDigests := wc.tree.Digests()
+
+			wc.ch <- change(wc.path, t2.Sub(t1), hash.Diff(digests, currentDigests), currentHash)
+
+			initialHash, digests = currentHash, currentDigests
+		}
 	}
 
 	for {
@@ -43,29 +115,60 @@ func worker(ctx context.Context, wg *sync.WaitGroup, wc *workerConfig) {
 				wc.ch <- paused(wc.path)
 			} else {
 				wc.ch <- resumed(wc.path)
+
+				// changes made while paused are not reported by inotify,
+				// pending change settle timer is dropped while paused
+				check(wc.debounce.pending())
 			}
 
-		case <-ticker.C:
+		case <-tick:
 			if ignoreTicks {
 				continue
 			}
 
-			t1 := time.Now()
-
-			currentHash, err := hash.FromPath(wc.path)
-			if err != nil {
-				wc.ch <- hashError(wc.path, err)
+			check(false)
 
+		case <-events:
+			if ignoreTicks {
 				continue
 			}
 
-			t2 := time.Now()
+			check(false)
 
-			if currentHash != initialHash {
-				wc.ch <- change(wc.path, t2.Sub(t1))
+		case <-wc.debounce.timer:
+			if ignoreTicks {
+				wc.debounce.timer = nil
 
-				initialHash = currentHash
+				continue
 			}
+
+			check(true)
+
+		case <-overflow:
+			wc.ch <- notifyFallback(wc.path)
+
+			events, overflow = nil, nil
+			tick = ticker.C
+
+			check(false)
 		}
 	}
 }
+
+// startNotifier starts inotify watch for event based watch modes,
+// notifier is nil for polling mode or when inotify watch can not be started,
+// in that case watcher falls back to polling.
+func startNotifier(wg *sync.WaitGroup, wc *workerConfig) *notifier {
+	if wc.mode == Poll {
+		return nil
+	}
+
+	n, err := newNotifier(wg, wc.path)
+	if err != nil {
+		wc.ch <- notifyError(wc.path, err)
+
+		return nil
+	}
+
+	return n
+}